@@ -4,40 +4,60 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
 	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sola-scriptura-search-api/internal/app"
 	"github.com/sola-scriptura-search-api/internal/config"
 	"github.com/sola-scriptura-search-api/internal/handlers"
+	"github.com/sola-scriptura-search-api/internal/metrics"
 	"github.com/sola-scriptura-search-api/internal/middleware"
-	"github.com/sola-scriptura-search-api/internal/repository"
-	"github.com/sola-scriptura-search-api/internal/repository/postgres"
-	"github.com/sola-scriptura-search-api/internal/repository/vertex"
-	"github.com/sola-scriptura-search-api/internal/services"
 	"github.com/sola-scriptura-search-api/pkg/schema/db"
-	pkgservices "github.com/sola-scriptura-search-api/pkg/schema/services"
 )
 
 func main() {
 	// Load .env file if present
 	_ = godotenv.Load()
 
+	// Structured JSON logs; the standard log package is routed through the same handler
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
 	// Get configuration
 	cfg := config.GetConfig()
+	if err := cfg.ValidateScope(); err != nil {
+		log.Fatalf("Invalid deployment scope: %v", err)
+	}
 
 	// Create Echo instance
 	e := echo.New()
 	e.HideBanner = true
+	e.HTTPErrorHandler = middleware.HTTPErrorHandler
+	e.Validator = middleware.NewValidator()
+	ipExtractor, err := middleware.IPExtractor(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	e.IPExtractor = ipExtractor
 
 	// Middleware
-	e.Use(echomiddleware.Logger())
+	e.Use(middleware.RequestIDMiddleware())
+	e.Use(middleware.RequestLoggerMiddleware())
 	e.Use(echomiddleware.Recover())
 	e.Use(middleware.CORSMiddleware())
+	e.Use(middleware.MetricsMiddleware())
+
+	// Prometheus metrics, served outside the API prefix alongside the root check
+	metrics.Register(prometheus.DefaultRegisterer)
+	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 
 	// Initialize PostgreSQL
 	ctx := context.Background()
@@ -47,51 +67,78 @@ func main() {
 	log.Println("Database initialization complete")
 
 	// Create repositories
-	pgDB := db.GetPostgres()
-	topicRepo := postgres.NewTopicRepository(pgDB)
-
-	// Create vector search repository based on configuration
-	var vectorRepo repository.VectorSearchRepository
-	var vertexRepo *vertex.VectorSearchRepository // For cleanup
-
-	switch cfg.VectorBackend {
-	case "vertex":
-		log.Println("Using Vertex AI Vector Search backend")
-		vertexCfg := vertex.Config{
-			ProjectID:            cfg.VertexProjectID,
-			Location:             cfg.VertexLocation,
-			IndexEndpointID:      cfg.VertexIndexEndpointID,
-			DeployedIndexID:      cfg.VertexDeployedIndexID,
-			PublicEndpointDomain: cfg.VertexPublicEndpointDomain,
-		}
-		var err error
-		vertexRepo, err = vertex.NewVectorSearchRepository(ctx, vertexCfg, pgDB)
-		if err != nil {
-			log.Fatalf("Failed to create Vertex AI vector repository: %v", err)
-		}
-		vectorRepo = vertexRepo
-	default:
-		log.Println("Using pgvector backend (unindexed)")
-		vectorRepo = postgres.NewVectorSearchRepository(pgDB)
+	backend, err := app.NewBackend(ctx, cfg, db.GetPostgres())
+	if err != nil {
+		log.Fatalf("Failed to create repositories: %v", err)
 	}
 
 	// Create services
-	embeddingsSvc := pkgservices.GetEmbeddingsService()
-	if err := pkgservices.GetInitError(); err != nil {
+	embeddingsSvc, err := app.NewEmbeddings()
+	if err != nil {
 		log.Fatalf("Failed to initialize embeddings service: %v", err)
 	}
+	embeddingsSvc.SetCacheLookupHook(func(hit bool) {
+		result := "miss"
+		if hit {
+			result = "hit"
+		}
+		metrics.EmbeddingCacheRequests.WithLabelValues(result).Inc()
+	})
+	embeddingsSvc.SetReconnectHook(metrics.EmbedderReconnects.Inc)
+
+	// Fail fast on a model/index dimension mismatch rather than on the first search
+	dims, err := embeddingsSvc.Check(ctx)
+	if err != nil {
+		log.Fatalf("Embedding dimension check failed: %v", err)
+	}
+	log.Printf("Embedding dimension check passed (%d dimensions)", dims)
+
+	vectorSearchSvc := backend.NewVectorSearchService(embeddingsSvc)
+	log.Printf("Topic stop words: %s", strings.Join(vectorSearchSvc.StopWords(), ","))
+
+	// Detect query/ingestion embedding drift before taking traffic
+	if cfg.EmbeddingCheckVerse != "" {
+		similarity, err := vectorSearchSvc.CheckEmbeddingConsistency(ctx, cfg.EmbeddingCheckVerse, cfg.EmbeddingCheckMinSimilarity)
+		if err != nil {
+			log.Fatalf("Embedding consistency check failed: %v", err)
+		}
+		log.Printf("Embedding consistency check passed for %s (similarity %.4f)", cfg.EmbeddingCheckVerse, similarity)
+	}
 
-	vectorSearchSvc := services.NewVectorSearchService(vectorRepo, topicRepo, embeddingsSvc)
+	// Prime the embedder and vector backend so the first search is not a cold start
+	if cfg.WarmupOnStart {
+		start := time.Now()
+		if err := vectorSearchSvc.Warmup(ctx); err != nil {
+			log.Fatalf("Warm-up failed: %v", err)
+		}
+		log.Printf("Warm-up complete in %dms", time.Since(start).Milliseconds())
+	}
 
 	// Create API group with prefix
 	api := e.Group(cfg.APIPrefix)
 
 	// Register handlers
-	healthHandler := handlers.NewHealthHandler()
+	healthHandler := handlers.NewHealthHandler(vectorSearchSvc)
 	healthHandler.RegisterRoutes(api)
 
+	// Endpoints that call the embedder share one rate limit per client
+	rateLimit := middleware.RateLimitMiddleware()
+
 	searchHandler := handlers.NewSearchHandler(vectorSearchSvc)
-	searchHandler.RegisterRoutes(api)
+	searchHandler.RegisterRoutes(api, rateLimit)
+
+	embedHandler := handlers.NewEmbedHandler(vectorSearchSvc)
+	embedHandler.RegisterRoutes(api, rateLimit)
+
+	topicHandler := handlers.NewTopicHandler(vectorSearchSvc)
+	topicHandler.RegisterRoutes(api, rateLimit)
+
+	verseHandler := handlers.NewVerseHandler(vectorSearchSvc)
+	verseHandler.RegisterRoutes(api)
+
+	// OpenAPI spec and Swagger UI at the root
+	docsHandler := handlers.NewDocsHandler()
+	docsHandler.RegisterRoutes(e.Group(""))
 
 	// Root health check
 	e.GET("/", func(c echo.Context) error {
@@ -130,10 +177,8 @@ func main() {
 	}
 
 	// Close Vertex AI client if used
-	if vertexRepo != nil {
-		if err := vertexRepo.Close(); err != nil {
-			log.Printf("Error closing Vertex AI client: %v", err)
-		}
+	if err := backend.Close(); err != nil {
+		log.Printf("Error closing Vertex AI client: %v", err)
 	}
 
 	log.Println("Server stopped")
@@ -0,0 +1,181 @@
+// selftest
+//
+// One-shot deployment smoke test that exercises the full search pipeline
+// without starting the HTTP server:
+//   1. Connect to PostgreSQL
+//   2. Build the repositories with the API's wiring (internal/app)
+//   3. Initialize the embeddings service
+//   4. Check the embedder returns vectors of the configured dimensions
+//   5. Embed a known query
+//   6. Run a vector search against the configured backend
+//   7. Assert a known verse appears in the top results
+//   8. Re-embed that verse with the configured text template and compare it to its
+//      stored vector, catching instruction/template drift or a mixed-template index
+//
+// This catches misconfiguration (wrong dimensions, stale index, wrong backend)
+// before the API takes traffic. Uses the same environment variables as the API.
+//
+// Usage:
+//   go run ./cmd/selftest
+//   go run ./cmd/selftest -query "the Lord is my shepherd" -expect Ps.23.1 -top 5
+//
+// Exits non-zero if any stage fails.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/sola-scriptura-search-api/internal/app"
+	"github.com/sola-scriptura-search-api/internal/config"
+	"github.com/sola-scriptura-search-api/internal/models"
+	"github.com/sola-scriptura-search-api/pkg/schema/db"
+	pkgservices "github.com/sola-scriptura-search-api/pkg/schema/services"
+)
+
+// stageResult records the outcome of a single pipeline stage
+type stageResult struct {
+	name     string
+	duration time.Duration
+	err      error
+	detail   string
+}
+
+func main() {
+	query := flag.String("query", "For God so loved the world that he gave his only begotten Son", "Query to embed and search")
+	expect := flag.String("expect", "John.3.16", "OSIS verse id that must appear in the top results")
+	topK := flag.Int("top", 10, "Number of results to search")
+	timeout := flag.Duration("timeout", 60*time.Second, "Overall timeout for the self-test")
+	minSimilarity := flag.Float64("min-similarity", 0, "Minimum stored/fresh embedding similarity (default EMBEDDING_CHECK_MIN_SIMILARITY)")
+	flag.Parse()
+
+	_ = godotenv.Load()
+	cfg := config.GetConfig()
+	if *minSimilarity <= 0 {
+		*minSimilarity = cfg.EmbeddingCheckMinSimilarity
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var results []stageResult
+	run := func(name string, fn func() (string, error)) bool {
+		start := time.Now()
+		detail, err := fn()
+		results = append(results, stageResult{name: name, duration: time.Since(start), err: err, detail: detail})
+		return err == nil
+	}
+
+	var (
+		backend       *app.Backend
+		embeddingsSvc *pkgservices.EmbeddingsService
+		embedding     []float64
+		verses        []models.ScoredVerse
+	)
+
+	ok := run("postgres", func() (string, error) {
+		if err := db.InitPostgres(ctx); err != nil {
+			return "", err
+		}
+		return "connected", nil
+	})
+
+	ok = ok && run("vector backend", func() (string, error) {
+		var err error
+		backend, err = app.NewBackend(ctx, cfg, db.GetPostgres())
+		if err != nil {
+			return "", err
+		}
+		if cfg.VectorFallback != "" {
+			return cfg.VectorBackend + " (fallback " + cfg.VectorFallback + ")", nil
+		}
+		return cfg.VectorBackend, nil
+	})
+
+	ok = ok && run("embedder", func() (string, error) {
+		var err error
+		embeddingsSvc, err = app.NewEmbeddings()
+		if err != nil {
+			return "", err
+		}
+		return "initialized", nil
+	})
+
+	ok = ok && run("dimensions", func() (string, error) {
+		dims, err := embeddingsSvc.Check(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d dimensions", dims), nil
+	})
+
+	ok = ok && run("embed query", func() (string, error) {
+		var err error
+		embedding, err = embeddingsSvc.EmbedQuery(ctx, *query)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d dimensions", len(embedding)), nil
+	})
+
+	ok = ok && run("vector search", func() (string, error) {
+		var err error
+		verses, err = backend.VectorRepo.SearchVersesByEmbedding(ctx, embedding, models.VectorSearchOptions{TopK: *topK})
+		if err != nil {
+			return "", err
+		}
+		if len(verses) == 0 {
+			return "", fmt.Errorf("no results returned")
+		}
+		return fmt.Sprintf("%d results", len(verses)), nil
+	})
+
+	ok = ok && run("expected verse", func() (string, error) {
+		for i, v := range verses {
+			if v.VerseID == *expect {
+				return fmt.Sprintf("%s at rank %d (score %.4f)", *expect, i+1, v.Score), nil
+			}
+		}
+		return "", fmt.Errorf("%s not in top %d (top result: %s)", *expect, *topK, verses[0].VerseID)
+	})
+
+	ok = ok && run("embedding drift", func() (string, error) {
+		similarity, err := backend.NewVectorSearchService(embeddingsSvc).CheckEmbeddingConsistency(ctx, *expect, *minSimilarity)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s similarity %.4f", *expect, similarity), nil
+	})
+
+	if backend != nil {
+		_ = backend.Close()
+	}
+	_ = db.ClosePostgres()
+
+	// Print summary
+	fmt.Println("Self-test summary")
+	fmt.Println("=================")
+	var total time.Duration
+	for _, r := range results {
+		total += r.duration
+		status := "PASS"
+		detail := r.detail
+		if r.err != nil {
+			status = "FAIL"
+			detail = r.err.Error()
+		}
+		fmt.Printf("  [%s] %-16s %8s  %s\n", status, r.name, r.duration.Round(time.Millisecond), detail)
+	}
+	fmt.Printf("  Total: %s\n", total.Round(time.Millisecond))
+
+	if !ok {
+		fmt.Println("RESULT: FAIL")
+		os.Exit(1)
+	}
+	fmt.Println("RESULT: PASS")
+}
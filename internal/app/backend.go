@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sola-scriptura-search-api/internal/config"
+	"github.com/sola-scriptura-search-api/internal/repository"
+	"github.com/sola-scriptura-search-api/internal/repository/postgres"
+	"github.com/sola-scriptura-search-api/internal/repository/vertex"
+	"github.com/sola-scriptura-search-api/internal/services"
+	pkgconfig "github.com/sola-scriptura-search-api/pkg/schema/config"
+	pkgservices "github.com/sola-scriptura-search-api/pkg/schema/services"
+)
+
+// Backend holds the repositories behind the search service, wired the same way for
+// the API server and the self-test
+type Backend struct {
+	VectorRepo repository.VectorSearchRepository
+	TopicRepo  repository.TopicRepository
+	VerseRepo  repository.VerseRepository
+
+	vertexRepo *vertex.VectorSearchRepository // For cleanup
+}
+
+// NewBackend creates the repositories configured by cfg over pgDB
+// With VECTOR_BACKEND=vertex the vector repository is Vertex AI, wrapped with the
+// pgvector fallback when VECTOR_FALLBACK is set; otherwise it is pgvector
+func NewBackend(ctx context.Context, cfg *config.Config, pgDB *sqlx.DB) (*Backend, error) {
+	if err := repository.ValidateDistanceMeasure(cfg.PGVectorDistanceMeasure); err != nil {
+		return nil, fmt.Errorf("invalid PGVECTOR_DISTANCE_MEASURE: %w", err)
+	}
+
+	b := &Backend{
+		TopicRepo: postgres.NewTopicRepository(pgDB, cfg.TopicCacheTTL, cfg.TopicSourceWeights, cfg.PGVectorDistanceMeasure),
+		VerseRepo: postgres.NewVerseRepository(pgDB),
+	}
+
+	switch cfg.VectorBackend {
+	case "vertex":
+		log.Println("Using Vertex AI Vector Search backend")
+		vertexCfg := vertex.Config{
+			ProjectID:            cfg.VertexProjectID,
+			Location:             cfg.VertexLocation,
+			IndexEndpointID:      cfg.VertexIndexEndpointID,
+			DeployedIndexID:      cfg.VertexDeployedIndexID,
+			PublicEndpointDomain: cfg.VertexPublicEndpointDomain,
+			MaxNeighborCount:     cfg.VertexMaxNeighborCount,
+			DistanceMeasure:      cfg.VertexDistanceMeasure,
+			Dimensions:           pkgconfig.GetConfig().EmbeddingDimensions,
+			VerseCacheSize:       cfg.VertexVerseCacheSize,
+			VerseCacheTTL:        cfg.VertexVerseCacheTTL,
+		}
+		vertexRepo, err := vertex.NewVectorSearchRepository(ctx, vertexCfg, pgDB)
+		if err != nil {
+			return nil, fmt.Errorf("create Vertex AI vector repository: %w", err)
+		}
+		b.vertexRepo = vertexRepo
+		b.VectorRepo = vertexRepo
+
+		switch cfg.VectorFallback {
+		case "":
+		case "pgvector":
+			log.Println("Falling back to pgvector when Vertex AI is unavailable")
+			b.VectorRepo = repository.NewFallbackVectorSearchRepository(vertexRepo, postgres.NewVectorSearchRepository(pgDB, cfg.PGVectorDistanceMeasure, cfg.PGVectorUseAugmented), "pgvector", vertex.IsRetryable)
+		default:
+			_ = vertexRepo.Close()
+			return nil, fmt.Errorf("unsupported VECTOR_FALLBACK %q; expected \"pgvector\" or empty", cfg.VectorFallback)
+		}
+	default:
+		log.Println("Using pgvector backend (unindexed)")
+		b.VectorRepo = postgres.NewVectorSearchRepository(pgDB, cfg.PGVectorDistanceMeasure, cfg.PGVectorUseAugmented)
+	}
+	return b, nil
+}
+
+// NewEmbeddings returns the shared embeddings service, or the error that stopped it initializing
+func NewEmbeddings() (*pkgservices.EmbeddingsService, error) {
+	embeddingsSvc := pkgservices.GetEmbeddingsService()
+	if err := pkgservices.GetInitError(); err != nil {
+		return nil, err
+	}
+	return embeddingsSvc, nil
+}
+
+// NewVectorSearchService creates the search service over the backend's repositories
+func (b *Backend) NewVectorSearchService(embeddingsSvc *pkgservices.EmbeddingsService) *services.VectorSearchService {
+	return services.NewVectorSearchService(b.VectorRepo, b.TopicRepo, b.VerseRepo, embeddingsSvc)
+}
+
+// Close closes the Vertex AI client, if one was created
+func (b *Backend) Close() error {
+	if b.vertexRepo != nil {
+		return b.vertexRepo.Close()
+	}
+	return nil
+}
@@ -2,9 +2,14 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/sola-scriptura-search-api/pkg/osis"
 )
 
 // Config holds all application configuration
@@ -20,6 +25,8 @@ type Config struct {
 
 	// Vector Search Backend: "pgvector" or "vertex"
 	VectorBackend string
+	// Backend retried when the vertex backend fails transiently: "pgvector" or "" (none)
+	VectorFallback string
 
 	// Vertex AI Vector Search settings (used when VectorBackend = "vertex")
 	VertexProjectID            string
@@ -27,6 +34,74 @@ type Config struct {
 	VertexIndexEndpointID      string
 	VertexDeployedIndexID      string
 	VertexPublicEndpointDomain string
+	VertexMaxNeighborCount     int           // Per-query NeighborCount cap; match the deployed index's limit
+	VertexVerseCacheSize       int           // Neighbor verse details kept in memory (0 disables)
+	VertexVerseCacheTTL        time.Duration // How long cached verse details are reused
+	VertexDistanceMeasure      string        // The index's distanceMeasureType: COSINE_DISTANCE, DOT_PRODUCT_DISTANCE or SQUARED_L2_DISTANCE
+
+	// Distance pgvector ranks by (same values as VertexDistanceMeasure, which it defaults to)
+	PGVectorDistanceMeasure string
+	// Search a verse's enriched embedding in pgvector where stored, matching what
+	// enrichment apply upserts to Vertex AI
+	PGVectorUseAugmented bool
+
+	// Deployment scope: when set, verses outside these books/testaments are never
+	// returned, and per-request filters are narrowed to within this set
+	AllowedBooks      []string // OSIS book ids, e.g. "Matt", "Rom"
+	AllowedTestaments []string // "OT" and/or "NT"
+
+	// Topic card source preference (earlier = preferred)
+	TopicSources           []string            // Default ordering for categories without a mapping
+	TopicSourcesByCategory map[string][]string // Per-category overrides, e.g. "person" -> Nave's first
+	// Multipliers on topic search scores by source, so search ranking follows the same
+	// preference as card selection (sources not listed keep 1.0). Scaled scores are also
+	// what the topic card minimum score is compared against
+	TopicSourceWeights map[string]float64
+
+	// Maximum topic cards returned by hybrid search (one per distinct concept)
+	MaxTopicCards int
+
+	// Also match topics on stems of query words, e.g. "forgiving" finds Forgiveness
+	TopicStemming bool
+	// Extra words dropped from topic queries on top of the built-in stop words,
+	// e.g. domain noise such as "bible" or "verse"
+	TopicStopWords []string
+	// Topic query synonyms merged over the built-in ones, e.g. {"end times": ["second coming"]}
+	TopicSynonyms map[string][]string
+
+	// Search result limits: the default applies when a request omits its limit, and
+	// larger requested limits are rejected
+	DefaultVerseLimit int
+	MaxVerseLimit     int
+	DefaultTopicLimit int
+	MaxTopicLimit     int
+
+	// Weight of verse popularity (0-1) added to the ranking score; 0 disables.
+	// Keep small (e.g. 0.02) so it only breaks near-ties between relevance scores
+	PopularityBoostWeight float64
+
+	// Startup embedding consistency check: re-embed this verse and compare to its stored
+	// embedding, refusing to start below the minimum similarity (empty verse = disabled)
+	EmbeddingCheckVerse         string
+	EmbeddingCheckMinSimilarity float64
+
+	// Embed a throwaway query and run one vector search before taking traffic, so the
+	// first real request does not pay connection and model cold-start costs
+	WarmupOnStart bool
+
+	// Verses either side of a result used when expanding to a pericope without boundary data
+	PericopeFallbackWindow int
+
+	// How long topic search results are cached in memory (0 disables)
+	TopicCacheTTL time.Duration
+
+	// Search rate limit per client IP: sustained requests per second and burst size
+	// of the token bucket (rate 0 disables)
+	RateLimitRPS   float64
+	RateLimitBurst int
+	// CIDR ranges of reverse proxies whose X-Forwarded-For is trusted for the client IP
+	// (empty = use the connection's remote address)
+	TrustedProxies []string
 }
 
 var (
@@ -48,10 +123,11 @@ func loadConfig() *Config {
 		APIVersion:  getEnv("API_VERSION", "1.0.0"),
 		APIPrefix:   getEnv("API_PREFIX", "/api/v1"),
 		Port:        getEnv("PORT", "8081"),
-		CORSOrigins: parseCORSOrigins(getEnv("CORS_ORIGINS", "http://localhost:5173,http://localhost:3000")),
+		CORSOrigins: parseList(getEnv("CORS_ORIGINS", "http://localhost:5173,http://localhost:3000")),
 
 		// Vector search backend configuration
-		VectorBackend: getEnv("VECTOR_BACKEND", "pgvector"), // "pgvector" or "vertex"
+		VectorBackend:  getEnv("VECTOR_BACKEND", "pgvector"), // "pgvector" or "vertex"
+		VectorFallback: getEnv("VECTOR_FALLBACK", ""),        // "pgvector" or "" (none)
 
 		// Vertex AI settings
 		VertexProjectID:            getEnv("VERTEX_PROJECT_ID", ""),
@@ -59,9 +135,64 @@ func loadConfig() *Config {
 		VertexIndexEndpointID:      getEnv("VERTEX_INDEX_ENDPOINT_ID", ""),
 		VertexDeployedIndexID:      getEnv("VERTEX_DEPLOYED_INDEX_ID", ""),
 		VertexPublicEndpointDomain: getEnv("VERTEX_PUBLIC_ENDPOINT_DOMAIN", ""),
+		VertexMaxNeighborCount:     getEnvInt("VERTEX_MAX_NEIGHBOR_COUNT", 1000),
+		VertexVerseCacheSize:       getEnvInt("VERTEX_VERSE_CACHE_SIZE", 5000),
+		VertexVerseCacheTTL:        getEnvDuration("VERTEX_VERSE_CACHE_TTL", time.Hour),
+		VertexDistanceMeasure:      getEnv("VERTEX_DISTANCE_MEASURE", "COSINE_DISTANCE"),
+
+		PGVectorDistanceMeasure: getEnv("PGVECTOR_DISTANCE_MEASURE", getEnv("VERTEX_DISTANCE_MEASURE", "COSINE_DISTANCE")),
+		PGVectorUseAugmented:    getEnvBool("PGVECTOR_USE_AUGMENTED_EMBEDDING", false),
+
+		// Deployment scope (empty = whole canon)
+		AllowedBooks:      parseList(getEnv("ALLOWED_BOOKS", "")),
+		AllowedTestaments: parseTestaments(getEnv("ALLOWED_TESTAMENTS", "")),
+
+		// Topic source preference
+		TopicSources:           parseList(getEnv("TOPIC_SOURCES", "claude_4.5_opus,torreys_topical_textbook,naves_topical_bible")),
+		TopicSourcesByCategory: parseListMap(getEnv("TOPIC_SOURCES_BY_CATEGORY", defaultTopicSourcesByCategory)),
+		TopicSourceWeights:     parseFloatMap(getEnv("TOPIC_SOURCE_WEIGHTS", defaultTopicSourceWeights)),
+		MaxTopicCards:          getEnvInt("MAX_TOPIC_CARDS", 3),
+		TopicStemming:          getEnvBool("TOPIC_STEMMING", true),
+		TopicStopWords:         parseList(getEnv("TOPIC_STOP_WORDS", "")),
+		TopicSynonyms:          parseListMap(getEnv("TOPIC_SYNONYMS", "{}")),
+
+		DefaultVerseLimit: getEnvInt("DEFAULT_VERSE_LIMIT", 10),
+		MaxVerseLimit:     getEnvInt("MAX_VERSE_LIMIT", 50),
+		DefaultTopicLimit: getEnvInt("DEFAULT_TOPIC_LIMIT", 5),
+		MaxTopicLimit:     getEnvInt("MAX_TOPIC_LIMIT", 50),
+
+		PopularityBoostWeight: getEnvFloat("POPULARITY_BOOST_WEIGHT", 0),
+
+		EmbeddingCheckVerse:         getEnv("EMBEDDING_CHECK_VERSE", ""),
+		EmbeddingCheckMinSimilarity: getEnvFloat("EMBEDDING_CHECK_MIN_SIMILARITY", 0.98),
+
+		WarmupOnStart: getEnvBool("WARMUP_ON_START", false),
+
+		PericopeFallbackWindow: getEnvInt("PERICOPE_FALLBACK_WINDOW", 2),
+
+		TopicCacheTTL: getEnvDuration("TOPIC_CACHE_TTL", 10*time.Minute),
+
+		RateLimitRPS:   getEnvFloat("RATE_LIMIT_RPS", 5),
+		RateLimitBurst: getEnvInt("RATE_LIMIT_BURST", 10),
+		TrustedProxies: parseList(getEnv("TRUSTED_PROXIES", "")),
 	}
 }
 
+// defaultTopicSourcesByCategory prefers classical indexes for people and places,
+// where Nave's is more exhaustive than the concept-focused Claude curation
+const defaultTopicSourcesByCategory = `{
+	"person": ["naves_topical_bible", "torreys_topical_textbook", "claude_4.5_opus"],
+	"place":  ["naves_topical_bible", "torreys_topical_textbook", "claude_4.5_opus"]
+}`
+
+// defaultTopicSourceWeights mirrors the default TopicSources order: a Nave's match must
+// be about 10% stronger than a Claude match to outrank it
+const defaultTopicSourceWeights = `{
+	"claude_4.5_opus": 1.0,
+	"torreys_topical_textbook": 0.95,
+	"naves_topical_bible": 0.9
+}`
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -69,17 +200,108 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-func parseCORSOrigins(value string) []string {
-	var origins []string
-	if err := json.Unmarshal([]byte(value), &origins); err == nil {
-		return origins
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return defaultValue
+		}
+		return i
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return defaultValue
+		}
+		return b
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return defaultValue
+		}
+		return f
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return defaultValue
+		}
+		return d
+	}
+	return defaultValue
+}
+
+// parseList accepts either a JSON array or a comma-separated list
+func parseList(value string) []string {
+	var items []string
+	if err := json.Unmarshal([]byte(value), &items); err == nil {
+		return items
 	}
 	parts := strings.Split(value, ",")
-	origins = make([]string, 0, len(parts))
+	items = make([]string, 0, len(parts))
 	for _, p := range parts {
 		if trimmed := strings.TrimSpace(p); trimmed != "" {
-			origins = append(origins, trimmed)
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
+
+// parseListMap parses a JSON object of string lists, e.g. {"person": ["a", "b"]}
+// Invalid input yields an empty map
+func parseListMap(value string) map[string][]string {
+	m := make(map[string][]string)
+	if err := json.Unmarshal([]byte(value), &m); err != nil {
+		return map[string][]string{}
+	}
+	return m
+}
+
+// parseFloatMap parses a JSON object of numbers, e.g. {"a": 1.0, "b": 0.9}
+// Invalid input yields an empty map
+func parseFloatMap(value string) map[string]float64 {
+	m := make(map[string]float64)
+	if err := json.Unmarshal([]byte(value), &m); err != nil {
+		return map[string]float64{}
+	}
+	return m
+}
+
+// ValidateScope returns an error for an ALLOWED_BOOKS entry that is not an OSIS book id
+// or an ALLOWED_TESTAMENTS entry other than OT or NT; a typo would otherwise silently
+// narrow every search to nothing
+func (c *Config) ValidateScope() error {
+	for _, book := range c.AllowedBooks {
+		if _, ok := osis.BookByID(book); !ok {
+			return fmt.Errorf("ALLOWED_BOOKS: %q is not an OSIS book id (e.g. Rom)", book)
+		}
+	}
+	for _, testament := range c.AllowedTestaments {
+		if testament != "OT" && testament != "NT" {
+			return fmt.Errorf("ALLOWED_TESTAMENTS: %q is not OT or NT", testament)
 		}
 	}
-	return origins
+	return nil
+}
+
+// parseTestaments parses a testament list, normalizing to "OT"/"NT"
+func parseTestaments(value string) []string {
+	items := parseList(value)
+	for i, item := range items {
+		items[i] = strings.ToUpper(item)
+	}
+	return items
 }
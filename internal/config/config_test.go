@@ -0,0 +1,30 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		books      []string
+		testaments []string
+		wantErr    string
+	}{
+		{name: "whole canon"},
+		{name: "valid", books: []string{"Rom", "1Cor"}, testaments: []string{"NT"}},
+		{name: "book name", books: []string{"Romans"}, wantErr: "ALLOWED_BOOKS"},
+		{name: "testament typo", testaments: []string{"NEW"}, wantErr: "ALLOWED_TESTAMENTS"},
+	}
+	for _, tt := range tests {
+		cfg := &Config{AllowedBooks: tt.books, AllowedTestaments: tt.testaments}
+		err := cfg.ValidateScope()
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: error %v, want one mentioning %s", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sola-scriptura-search-api/internal/config"
+	"github.com/sola-scriptura-search-api/internal/middleware"
+	"github.com/sola-scriptura-search-api/internal/models"
+	"github.com/sola-scriptura-search-api/internal/openapi"
+)
+
+// DocsHandler serves the OpenAPI description of the API and a Swagger UI for it
+type DocsHandler struct {
+	spec *openapi.Document
+}
+
+// NewDocsHandler creates a new docs handler, building the spec from the models
+func NewDocsHandler() *DocsHandler {
+	cfg := config.GetConfig()
+	return &DocsHandler{
+		spec: buildSpec(openapi.Info{Title: cfg.APITitle, Version: cfg.APIVersion}, cfg.APIPrefix, middleware.LimitAliases(cfg)),
+	}
+}
+
+// buildSpec describes every route under the API prefix
+// Keep in sync with the RegisterRoutes methods; schemas follow the models' struct tags
+// aliases are the validator's tag aliases, so documented bounds match enforced ones
+func buildSpec(info openapi.Info, prefix string, aliases map[string]string) *openapi.Document {
+	doc := openapi.NewDocument(info, prefix)
+	doc.SetValidateAliases(aliases)
+
+	limit := openapi.Param{Name: "limit", Type: "integer"}
+	badID := []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}
+
+	for _, op := range []openapi.Operation{
+		{Method: "POST", Path: "/search", Tag: "search", Summary: "Unified search (semantic, keyword, hybrid or reference)",
+			Query:   []openapi.Param{{Name: "explain", Type: "boolean"}},
+			Request: models.SearchRequest{}, Response: models.SearchResponse{}, PlainText: true,
+			Errors: []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusTooManyRequests, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/search/hybrid", Tag: "search", Summary: "Hybrid search with topic cards",
+			Request: models.HybridSearchRequest{}, Response: models.HybridSearchResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusTooManyRequests, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/search/stream", Tag: "search", Summary: "Semantic search streamed as server-sent events (result*, then done or error)",
+			Query: []openapi.Param{{Name: "q", Type: "string"}, limit, {Name: "context_window", Type: "integer"},
+				{Name: "expand", Type: "string", Enum: []string{models.ExpandPericope}}},
+			Response: models.Citation{}, EventStream: true,
+			Errors: []int{http.StatusBadRequest, http.StatusTooManyRequests, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/search/text", Tag: "search", Summary: "Phrase or exact-wording search over verse text",
+			Request: models.TextSearchRequest{}, Response: []models.Citation{}, PlainText: true,
+			Errors: []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusTooManyRequests, http.StatusInternalServerError}},
+
+		{Method: "POST", Path: "/embed", Tag: "embeddings", Summary: "Embedding of arbitrary text as a query or document",
+			Request: models.EmbedRequest{}, Response: models.EmbedResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusTooManyRequests, http.StatusInternalServerError}},
+
+		{Method: "GET", Path: "/topics", Tag: "topics", Summary: "List topics",
+			Query: []openapi.Param{limit, {Name: "offset", Type: "integer"},
+				{Name: "sort", Type: "string", Enum: []string{models.TopicSortName, models.TopicSortVerseCount, models.TopicSortSource}},
+				{Name: "category", Type: "string"}, {Name: "source", Type: "string"}},
+			Response: models.TopicListResponse{}, Errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/topics/:id", Tag: "topics", Summary: "Topic with its verses by importance tier",
+			Query: []openapi.Param{limit, {Name: "offset", Type: "integer"}, {Name: "book", Type: "string"},
+				{Name: "max_tier", Type: "integer"}},
+			Response: models.TopicDetail{}, Errors: badID},
+		{Method: "GET", Path: "/topics/:id/coverage", Tag: "topics", Summary: "Per-tier mapped vs canonical verse counts",
+			Response: models.TopicCoverage{}, Errors: badID},
+		{Method: "POST", Path: "/topics/:id/search", Tag: "topics", Summary: "Semantic search within a topic's verses",
+			Request: models.TopicVerseSearchRequest{}, Response: []models.Citation{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusTooManyRequests, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/topics/:id/related", Tag: "topics", Summary: "Parent, sibling and child topics in the hierarchy",
+			Response: models.RelatedTopics{}, Errors: badID},
+
+		{Method: "GET", Path: "/verses/random", Tag: "verses", Summary: "A random verse, optionally within a testament or book",
+			Query: []openapi.Param{{Name: "testament", Type: "string", Enum: []string{models.TestamentOld, models.TestamentNew}},
+				{Name: "book", Type: "string"}},
+			Response: models.Citation{}, Errors: badID},
+		{Method: "POST", Path: "/verses/batch", Tag: "verses", Summary: "Verses by OSIS id or reference, in the requested order",
+			Request: models.VerseBatchRequest{}, Response: []models.Citation{},
+			Errors: []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/verses/:osisID", Tag: "verses", Summary: "Verse by OSIS id",
+			Response: models.Citation{}, Errors: badID},
+		{Method: "GET", Path: "/verses/:osisID/similar", Tag: "verses", Summary: "Verses closest in meaning to a verse",
+			Query: []openapi.Param{limit}, Response: []models.Citation{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/verses/:osisID/cross-references", Tag: "verses", Summary: "Cross-references of a verse",
+			Query: []openapi.Param{limit}, Response: []models.Citation{}, Errors: badID},
+		{Method: "GET", Path: "/verses/:osisID/topics", Tag: "verses", Summary: "Topics a verse belongs to, by its importance tier in each",
+			Query: []openapi.Param{limit}, Response: []models.VerseTopic{}, Errors: badID},
+		{Method: "GET", Path: "/verses/:osisID/annotations", Tag: "verses", Summary: "Theological annotations of a verse from enrichment",
+			Response: models.VerseAnnotations{}, Errors: badID},
+		{Method: "GET", Path: "/passages", Tag: "verses", Summary: "Verses of a reference range within one book",
+			Query: []openapi.Param{{Name: "ref", Type: "string"}}, Response: []models.Citation{},
+			Errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/books", Tag: "verses", Summary: "Books in canonical order with testament and genre",
+			Response: []models.BookInfo{}, Errors: []int{http.StatusInternalServerError}},
+		{Method: "GET", Path: "/compare", Tag: "verses", Summary: "Cosine similarity of two verses' stored embeddings",
+			Query:    []openapi.Param{{Name: "a", Type: "string"}, {Name: "b", Type: "string"}},
+			Response: models.VerseComparison{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError}},
+
+		{Method: "GET", Path: "/health", Tag: "health", Summary: "Liveness", Response: HealthResponse{}},
+		{Method: "GET", Path: "/health/postgres", Tag: "health", Summary: "PostgreSQL connectivity (503 with the same body when down)",
+			Response: DatabaseHealthResponse{}},
+		{Method: "GET", Path: "/health/vector", Tag: "health", Summary: "Vector backend connectivity (503 with the same body when down)",
+			Response: VectorHealthResponse{}},
+		{Method: "GET", Path: "/health/embeddings", Tag: "health", Summary: "Embedding provider connectivity (503 with the same body when down)",
+			Response: EmbeddingsHealthResponse{}},
+	} {
+		doc.Add(op)
+	}
+	return doc
+}
+
+// OpenAPI handles GET /openapi.json
+func (h *DocsHandler) OpenAPI(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.spec)
+}
+
+// swaggerUI renders /openapi.json with Swagger UI loaded from a CDN
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>API docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});</script>
+</body>
+</html>`
+
+// Docs handles GET /docs
+func (h *DocsHandler) Docs(c echo.Context) error {
+	return c.HTML(http.StatusOK, swaggerUI)
+}
+
+// RegisterRoutes registers the docs routes; mount on the root rather than the API prefix
+func (h *DocsHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/openapi.json", h.OpenAPI)
+	g.GET("/docs", h.Docs)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sola-scriptura-search-api/internal/models"
+	"github.com/sola-scriptura-search-api/internal/services"
+)
+
+// EmbedHandler exposes the API's embedding model for arbitrary text
+type EmbedHandler struct {
+	vectorSearch *services.VectorSearchService
+}
+
+// NewEmbedHandler creates a new embed handler
+func NewEmbedHandler(vectorSearch *services.VectorSearchService) *EmbedHandler {
+	return &EmbedHandler{
+		vectorSearch: vectorSearch,
+	}
+}
+
+// Embed handles POST /embed - the embedding of text as a query (default) or document
+func (h *EmbedHandler) Embed(c echo.Context) error {
+	var req models.EmbedRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(http.StatusBadRequest, models.CodeInvalidBody, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(req.Text) == "" {
+		return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "text must not be blank")
+	}
+	if req.TaskType == "" {
+		req.TaskType = models.EmbedTaskQuery
+	}
+
+	ctx := c.Request().Context()
+	var embedding []float64
+	var err error
+	switch req.TaskType {
+	case models.EmbedTaskQuery:
+		embedding, err = h.vectorSearch.EmbedQuery(ctx, req.Text)
+	case models.EmbedTaskDocument:
+		embedding, err = h.vectorSearch.EmbedDocument(ctx, req.Text)
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Embedding failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, models.EmbedResponse{
+		Embedding:  embedding,
+		Dimensions: len(embedding),
+		TaskType:   req.TaskType,
+	})
+}
+
+// RegisterRoutes registers the embed route, applying m to it
+func (h *EmbedHandler) RegisterRoutes(g *echo.Group, m ...echo.MiddlewareFunc) {
+	g.POST("/embed", h.Embed, m...)
+}
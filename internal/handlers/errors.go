@@ -0,0 +1,11 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sola-scriptura-search-api/internal/models"
+)
+
+// apiError returns an HTTP error rendered as a models.ErrorResponse with code
+func apiError(status int, code models.ErrorCode, message string) *echo.HTTPError {
+	return echo.NewHTTPError(status, models.ErrorDetail{Code: code, Message: message})
+}
@@ -2,17 +2,23 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sola-scriptura-search-api/internal/config"
+	"github.com/sola-scriptura-search-api/internal/services"
+	pkgconfig "github.com/sola-scriptura-search-api/pkg/schema/config"
 	"github.com/sola-scriptura-search-api/pkg/schema/db"
 )
 
 // HealthHandler handles health check endpoints
-type HealthHandler struct{}
+type HealthHandler struct {
+	searchService *services.VectorSearchService
+}
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+func NewHealthHandler(searchService *services.VectorSearchService) *HealthHandler {
+	return &HealthHandler{searchService: searchService}
 }
 
 // HealthResponse is the response for basic health check
@@ -26,6 +32,24 @@ type DatabaseHealthResponse struct {
 	Database string `json:"database"`
 }
 
+// VectorHealthResponse is the response for vector backend health check
+type VectorHealthResponse struct {
+	Status    string  `json:"status"`
+	Backend   string  `json:"backend"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// EmbeddingsHealthResponse is the response for embeddings service health check
+type EmbeddingsHealthResponse struct {
+	Status             string  `json:"status"`
+	Provider           string  `json:"provider"`
+	Dimensions         int     `json:"dimensions"`
+	ExpectedDimensions int     `json:"expected_dimensions"`
+	LatencyMs          float64 `json:"latency_ms"`
+	Error              string  `json:"error,omitempty"`
+}
+
 // Health handles GET /health
 func (h *HealthHandler) Health(c echo.Context) error {
 	return c.JSON(http.StatusOK, HealthResponse{
@@ -63,8 +87,52 @@ func (h *HealthHandler) PostgresHealth(c echo.Context) error {
 	})
 }
 
+// VectorHealth handles GET /health/vector
+// Issues a minimal query against the configured vector backend so readiness probes
+// fail on a misconfigured or unreachable index
+func (h *HealthHandler) VectorHealth(c echo.Context) error {
+	start := time.Now()
+	err := h.searchService.PingVectorBackend(c.Request().Context())
+
+	resp := VectorHealthResponse{
+		Status:    "connected",
+		Backend:   config.GetConfig().VectorBackend,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		resp.Status = "error"
+		resp.Error = err.Error()
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// EmbeddingsHealth handles GET /health/embeddings
+// Embeds a fixed string through the configured provider and checks the dimensions,
+// returning 503 so orchestrators can gate traffic while the provider is down
+func (h *HealthHandler) EmbeddingsHealth(c echo.Context) error {
+	start := time.Now()
+	dims, expected, err := h.searchService.CheckEmbeddings(c.Request().Context())
+
+	resp := EmbeddingsHealthResponse{
+		Status:             "healthy",
+		Provider:           pkgconfig.GetConfig().EmbeddingProvider,
+		Dimensions:         dims,
+		ExpectedDimensions: expected,
+		LatencyMs:          float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		resp.Status = "error"
+		resp.Error = err.Error()
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 // RegisterRoutes registers health check routes
 func (h *HealthHandler) RegisterRoutes(g *echo.Group) {
 	g.GET("/health", h.Health)
 	g.GET("/health/postgres", h.PostgresHealth)
+	g.GET("/health/vector", h.VectorHealth)
+	g.GET("/health/embeddings", h.EmbeddingsHealth)
 }
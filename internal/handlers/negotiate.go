@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sola-scriptura-search-api/internal/models"
+	"github.com/sola-scriptura-search-api/pkg/osis"
+)
+
+// negotiate writes the plain text rendered by text when the Accept header prefers
+// text/plain over JSON, and v as JSON otherwise (including when Accept is absent or */*)
+func negotiate(c echo.Context, status int, v any, text func() string) error {
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+	if prefersPlainText(c.Request().Header.Get(echo.HeaderAccept)) {
+		return c.String(status, text())
+	}
+	return c.JSON(status, v)
+}
+
+// prefersPlainText reports whether accept ranks text/plain above application/json
+// Wildcards count toward JSON so it stays the default
+func prefersPlainText(accept string) bool {
+	var textQ, jsonQ float64
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if name == "q" {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case echo.MIMETextPlain, "text/*":
+			textQ = max(textQ, q)
+		case echo.MIMEApplicationJSON, "application/*", "*/*":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return textQ > 0 && textQ > jsonQ
+}
+
+// plainTextCitations renders one citation per line as "Book Chapter:Verse — text (score)"
+// The score is the relevance score, else the fusion score, and is omitted when neither is set
+func plainTextCitations(citations []models.Citation) string {
+	var b strings.Builder
+	for _, cite := range citations {
+		book := cite.Book
+		if known, ok := osis.BookByID(cite.Book); ok {
+			book = known.Name
+		}
+		fmt.Fprintf(&b, "%s %d:%d", book, cite.Chapter, cite.Verse)
+		if cite.EndVerse > cite.Verse {
+			fmt.Fprintf(&b, "-%d", cite.EndVerse)
+		}
+		fmt.Fprintf(&b, " — %s", cite.Text)
+		switch {
+		case cite.RelevanceScore != nil:
+			fmt.Fprintf(&b, " (%.3f)", *cite.RelevanceScore)
+		case cite.FusionScore != nil:
+			fmt.Fprintf(&b, " (%.3f)", *cite.FusionScore)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
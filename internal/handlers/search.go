@@ -1,113 +1,278 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sola-scriptura-search-api/internal/config"
 	"github.com/sola-scriptura-search-api/internal/models"
 	"github.com/sola-scriptura-search-api/internal/services"
 )
 
 // SearchHandler handles search endpoints
 type SearchHandler struct {
-	vectorSearch *services.VectorSearchService
+	vectorSearch      *services.VectorSearchService
+	defaultVerseLimit int
+	defaultTopicLimit int
+	maxVerseLimit     int // For query-string limits, which bypass the validator
 }
 
-// NewSearchHandler creates a new search handler
+// NewSearchHandler creates a new search handler using the configured default limits
+// Maximums are enforced by the validator's verse_limit/topic_limit tag aliases
 func NewSearchHandler(vectorSearch *services.VectorSearchService) *SearchHandler {
+	cfg := config.GetConfig()
 	return &SearchHandler{
-		vectorSearch: vectorSearch,
+		vectorSearch:      vectorSearch,
+		defaultVerseLimit: min(cfg.DefaultVerseLimit, cfg.MaxVerseLimit),
+		defaultTopicLimit: min(cfg.DefaultTopicLimit, cfg.MaxTopicLimit),
+		maxVerseLimit:     cfg.MaxVerseLimit,
 	}
 }
 
-// SemanticSearch handles POST /search - semantic verse search
-func (h *SearchHandler) SemanticSearch(c echo.Context) error {
-	ctx := c.Request().Context()
-
-	var req models.SemanticSearchRequest
+// Search handles POST /search - unified search dispatching on mode (default semantic)
+// See models.SearchResponse for the response shape of each mode; with Accept: text/plain
+// the results are rendered one citation per line instead. ?explain=true attaches each
+// result's scoring internals (raw distance, similarity, fusion contributions)
+func (h *SearchHandler) Search(c echo.Context) error {
+	var req models.SearchRequest
 	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+		return apiError(http.StatusBadRequest, models.CodeInvalidBody, "Invalid request body")
 	}
-
-	if req.Query == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "Query is required")
+	if err := c.Validate(&req); err != nil {
+		return err
 	}
-
-	limit := req.Limit
-	if limit <= 0 || limit > 50 {
-		limit = 10
+	if value := c.QueryParam("explain"); value != "" {
+		explain, err := strconv.ParseBool(value)
+		if err != nil {
+			return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "explain must be true or false")
+		}
+		req.Explain = explain
 	}
 
-	citations, err := h.vectorSearch.SearchVersesCitations(ctx, req.Query, limit)
+	resp, err := h.search(c, req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Search failed: "+err.Error())
+		return err
 	}
-
-	return c.JSON(http.StatusOK, models.SemanticSearchResponse{
-		Query:   req.Query,
-		Results: citations,
-	})
+	return negotiate(c, http.StatusOK, resp, func() string { return plainTextCitations(resp.Results) })
 }
 
 // HybridSearch handles POST /search/hybrid - searches both verses and topics
+// Kept for compatibility; equivalent to POST /search with mode "hybrid"
 func (h *SearchHandler) HybridSearch(c echo.Context) error {
-	ctx := c.Request().Context()
-
 	var req models.HybridSearchRequest
 	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+		return apiError(http.StatusBadRequest, models.CodeInvalidBody, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
 	}
 
-	if req.Query == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "Query is required")
+	resp, err := h.search(c, models.SearchRequest{
+		Query:               req.Query,
+		Mode:                models.SearchModeHybrid,
+		Limit:               req.VerseLimit,
+		TopicLimit:          req.TopicLimit,
+		VerseSearchOptions:  req.VerseSearchOptions,
+		TopicSearchOptions:  req.TopicSearchOptions,
+		HybridSearchOptions: req.HybridSearchOptions,
+	})
+	if err != nil {
+		return err
 	}
 
-	verseLimit := req.VerseLimit
-	if verseLimit <= 0 || verseLimit > 50 {
-		verseLimit = 10
+	topics := resp.Topics
+	if topics == nil {
+		topics = []models.ScoredTopic{}
 	}
+	return c.JSON(http.StatusOK, models.HybridSearchResponse{
+		Query:      resp.Query,
+		TopicCard:  resp.TopicCard,
+		TopicCards: resp.TopicCards,
+		ResourceMatches: models.ResourceMatches{
+			Topics: topics,
+		},
+		SemanticMatches: models.SemanticMatches{
+			Verses: resp.Results,
+		},
+	})
+}
 
-	topicLimit := req.TopicLimit
-	if topicLimit <= 0 || topicLimit > 50 {
-		topicLimit = 5
+// TextSearch handles POST /search/text - verses containing a phrase, or the exact
+// wording when exact is set, ranked by text relevance; no embeddings are involved
+// Also rendered as plain text with Accept: text/plain
+func (h *SearchHandler) TextSearch(c echo.Context) error {
+	var req models.TextSearchRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(http.StatusBadRequest, models.CodeInvalidBody, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	if req.Limit == 0 {
+		req.Limit = h.defaultVerseLimit
+	}
+	if err := validateVerseSearchOptions(req.VerseSearchOptions); err != nil {
+		return err
 	}
 
-	// Search verses
-	citations, err := h.vectorSearch.SearchVersesCitations(ctx, req.Query, verseLimit)
+	citations, err := h.vectorSearch.SearchPhrase(c.Request().Context(), req.Query, req.Exact, req.Limit, req.VerseSearchOptions)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Search failed: "+err.Error())
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Text search failed: "+err.Error())
 	}
+	return negotiate(c, http.StatusOK, citations, func() string { return plainTextCitations(citations) })
+}
+
+// StreamSearch handles GET /search/stream?q=...&limit=N - semantic search streamed as
+// server-sent events: one "result" event per citation (JSON) in rank order, then a
+// "done" event with the count. Optional context_window and expand=pericope enrich each
+// citation before it is sent; expansion runs in small rank-ordered batches, so the first
+// results arrive before the rest are expanded. Errors before the first result are
+// ordinary JSON errors; later failures end the stream with an "error" event.
+// Disconnecting cancels the search.
+func (h *SearchHandler) StreamSearch(c echo.Context) error {
+	ctx := c.Request().Context()
 
-	// Search topics by keywords
-	topics, err := h.vectorSearch.SearchTopics(ctx, req.Query, topicLimit)
+	query := strings.TrimSpace(c.QueryParam("q"))
+	if query == "" {
+		return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "q is required")
+	}
+	limit, err := limitParam(c, h.defaultVerseLimit, h.maxVerseLimit)
 	if err != nil {
-		c.Logger().Warnf("Topic search failed: %v", err)
-		topics = []models.ScoredTopic{}
+		return err
+	}
+	var opts models.VerseSearchOptions
+	if value := c.QueryParam("context_window"); value != "" {
+		opts.ContextWindow, err = strconv.Atoi(value)
+		if err != nil || opts.ContextWindow < 0 {
+			return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "context_window must be a non-negative integer")
+		}
+	}
+	switch opts.Expand = c.QueryParam("expand"); opts.Expand {
+	case "", models.ExpandPericope:
+	default:
+		return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "expand must be \"pericope\" if set")
 	}
 
-	// Get topic card if there's a strong match (score >= 0.9)
-	var topicCard *models.TopicCard
-	if len(topics) > 0 {
-		topicCard, err = h.vectorSearch.GetTopicCard(ctx, topics, 0.9, 10)
+	w := c.Response()
+	count := 0
+	startStream := func() {
+		w.Header().Set(echo.HeaderContentType, "text/event-stream")
+		w.Header().Set(echo.HeaderCacheControl, "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+		w.WriteHeader(http.StatusOK)
+	}
+	err = h.vectorSearch.StreamSearch(ctx, query, limit, opts, func(citation models.Citation) error {
+		if count == 0 {
+			startStream()
+		}
+		count++
+		return writeEvent(w, "result", citation)
+	})
+	if ctx.Err() != nil {
+		return nil // Client went away; nothing left to send
+	}
+	if count == 0 {
 		if err != nil {
-			c.Logger().Warnf("Topic card fetch failed: %v", err)
+			return apiError(http.StatusInternalServerError, models.CodeBackendError, "Search failed: "+err.Error())
 		}
+		startStream()
 	}
+	if err != nil {
+		return writeEvent(w, "error", models.ErrorDetail{Code: models.CodeBackendError, Message: "Search failed: " + err.Error()})
+	}
+	return writeEvent(w, "done", map[string]int{"count": count})
+}
 
-	return c.JSON(http.StatusOK, models.HybridSearchResponse{
-		Query:     req.Query,
-		TopicCard: topicCard,
-		ResourceMatches: models.ResourceMatches{
-			Topics: topics,
-		},
-		SemanticMatches: models.SemanticMatches{
-			Verses: citations,
-		},
-	})
+// writeEvent writes one server-sent event with a JSON data payload and flushes it
+func writeEvent(w *echo.Response, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encode %s event: %w", event, err)
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
+
+// search applies default limits to a tag-validated unified search request, checks the
+// rules tags cannot express and runs it
+func (h *SearchHandler) search(c echo.Context, req models.SearchRequest) (*models.SearchResponse, error) {
+	if req.Limit == 0 {
+		req.Limit = h.defaultVerseLimit
+	}
+	if req.TopicLimit == 0 {
+		req.TopicLimit = h.defaultTopicLimit
+	}
+
+	if err := validateVerseSearchOptions(req.VerseSearchOptions); err != nil {
+		return nil, err
+	}
+	if err := validateHybridSearchOptions(req.HybridSearchOptions); err != nil {
+		return nil, err
+	}
+	if (req.Fusion || req.Lexical) && req.Offset > 0 {
+		return nil, apiError(http.StatusBadRequest, models.CodeInvalidParameter, "offset is not supported with fusion or lexical")
+	}
+
+	resp, err := h.vectorSearch.Search(c.Request().Context(), req)
+	if errors.Is(err, services.ErrInvalidReference) {
+		return nil, apiError(http.StatusBadRequest, models.CodeInvalidReference, err.Error())
+	}
+	if err != nil {
+		return nil, apiError(http.StatusInternalServerError, models.CodeBackendError, "Search failed: "+err.Error())
+	}
+	return resp, nil
+}
+
+// osisBookID matches the shape of an OSIS book id such as "Gen" or "1Cor"
+var osisBookID = regexp.MustCompile(`^[1-4]?[A-Za-z]+$`)
+
+// validateVerseSearchOptions rejects verse filter combinations the validate tags cannot express
+func validateVerseSearchOptions(opts models.VerseSearchOptions) error {
+	if opts.MaxTextLength > 0 && opts.MinTextLength > opts.MaxTextLength {
+		return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "min_text_length cannot exceed max_text_length")
+	}
+	for _, book := range opts.Books {
+		if !osisBookID.MatchString(book) {
+			return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "Invalid OSIS book id: "+book)
+		}
+	}
+	if opts.MaxBookOrder > 0 && opts.MinBookOrder > opts.MaxBookOrder {
+		return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "min_book_order cannot exceed max_book_order")
+	}
+	if opts.BookRange != "" {
+		if _, ok := models.BookRanges[opts.BookRange]; !ok {
+			return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "Unknown book_range: "+opts.BookRange)
+		}
+		if opts.MinBookOrder > 0 || opts.MaxBookOrder > 0 {
+			return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "book_range cannot be combined with min_book_order or max_book_order")
+		}
+	}
+	return nil
+}
+
+// validateHybridSearchOptions rejects fusion weights that would produce NaN scores
+// Negative weights are already rejected by their validate tags
+func validateHybridSearchOptions(opts models.HybridSearchOptions) error {
+	if verseWeight, _, _ := opts.FusionWeights(); math.IsNaN(verseWeight) {
+		return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "verse_weight, topic_weight and lexical_weight cannot all be zero")
+	}
+	return nil
 }
 
-// RegisterRoutes registers search routes
-func (h *SearchHandler) RegisterRoutes(g *echo.Group) {
-	g.POST("/search", h.SemanticSearch)
-	g.POST("/search/hybrid", h.HybridSearch)
+// RegisterRoutes registers search routes, applying m to each
+func (h *SearchHandler) RegisterRoutes(g *echo.Group, m ...echo.MiddlewareFunc) {
+	g.POST("/search", h.Search, m...)
+	g.POST("/search/hybrid", h.HybridSearch, m...)
+	g.POST("/search/text", h.TextSearch, m...)
+	g.GET("/search/stream", h.StreamSearch, m...)
 }
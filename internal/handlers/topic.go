@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sola-scriptura-search-api/internal/config"
+	"github.com/sola-scriptura-search-api/internal/models"
+	"github.com/sola-scriptura-search-api/internal/repository"
+	"github.com/sola-scriptura-search-api/internal/services"
+)
+
+// TopicHandler handles topic endpoints
+type TopicHandler struct {
+	vectorSearch      *services.VectorSearchService
+	defaultVerseLimit int // Topic verse search limit, shared with search
+}
+
+// NewTopicHandler creates a new topic handler
+func NewTopicHandler(vectorSearch *services.VectorSearchService) *TopicHandler {
+	cfg := config.GetConfig()
+	return &TopicHandler{
+		vectorSearch:      vectorSearch,
+		defaultVerseLimit: min(cfg.DefaultVerseLimit, cfg.MaxVerseLimit),
+	}
+}
+
+// ListTopics handles GET /topics - paginated topic directory
+// Query params: limit (default 50, max 200), offset, sort (name|verse_count|source),
+// category and source filters
+func (h *TopicHandler) ListTopics(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limit, err := limitParam(c, 50, 200)
+	if err != nil {
+		return err
+	}
+	offset, err := offsetParam(c)
+	if err != nil {
+		return err
+	}
+
+	sort := c.QueryParam("sort")
+	if sort == "" {
+		sort = models.TopicSortName
+	}
+	switch sort {
+	case models.TopicSortName, models.TopicSortVerseCount, models.TopicSortSource:
+	default:
+		return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "sort must be one of: name, verse_count, source")
+	}
+
+	resp, err := h.vectorSearch.ListTopics(ctx, models.TopicListOptions{
+		Limit:    limit,
+		Offset:   offset,
+		Sort:     sort,
+		Category: c.QueryParam("category"),
+		Source:   c.QueryParam("source"),
+	})
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Topic listing failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetTopic handles GET /topics/:id - topic metadata with a page of its verses by importance tier
+// Query params: limit (default 100, max 500), offset, book (OSIS id or name) and
+// max_tier (1-3) to return only the more important tiers, e.g. 1 for essentials
+// Unknown ids are 404 TOPIC_NOT_FOUND, never an empty verse list
+func (h *TopicHandler) GetTopic(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	topicID, err := topicIDParam(c)
+	if err != nil {
+		return err
+	}
+	limit, err := limitParam(c, 100, 500)
+	if err != nil {
+		return err
+	}
+	offset, err := offsetParam(c)
+	if err != nil {
+		return err
+	}
+	maxTier := 0
+	if value := c.QueryParam("max_tier"); value != "" {
+		maxTier, err = strconv.Atoi(value)
+		if err != nil || maxTier < 1 || maxTier > 3 {
+			return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "max_tier must be between 1 and 3")
+		}
+	}
+
+	topic, err := h.vectorSearch.GetTopic(ctx, topicID, c.QueryParam("book"), models.TopicVerseOptions{
+		Limit:   limit,
+		Offset:  offset,
+		MaxTier: maxTier,
+	})
+	if errors.Is(err, services.ErrInvalidReference) {
+		return apiError(http.StatusBadRequest, models.CodeUnknownBook, "Unknown book; expected an OSIS id or name, e.g. Rom or Romans")
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return apiError(http.StatusNotFound, models.CodeTopicNotFound, "Topic not found")
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Topic lookup failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, topic)
+}
+
+// TopicCoverage handles GET /topics/:id/coverage - per-tier mapped vs canonical verse counts
+// Canonical counts come from definitions stored with scripts/audit -store; a topic
+// without one reports zero expected verses
+func (h *TopicHandler) TopicCoverage(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	topicID, err := topicIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	coverage, err := h.vectorSearch.GetTopicCoverage(ctx, topicID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apiError(http.StatusNotFound, models.CodeTopicNotFound, "Topic not found")
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Coverage lookup failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, coverage)
+}
+
+// RelatedTopics handles GET /topics/:id/related - parent, sibling and child topics
+func (h *TopicHandler) RelatedTopics(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	topicID, err := topicIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	related, err := h.vectorSearch.GetRelatedTopics(ctx, topicID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apiError(http.StatusNotFound, models.CodeTopicNotFound, "Topic not found")
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Related topic lookup failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, related)
+}
+
+// SearchTopicVerses handles POST /topics/:id/search - semantic search over only the
+// verses mapped to the topic, most similar first
+func (h *TopicHandler) SearchTopicVerses(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	topicID, err := topicIDParam(c)
+	if err != nil {
+		return err
+	}
+	var req models.TopicVerseSearchRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(http.StatusBadRequest, models.CodeInvalidBody, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	if req.Limit == 0 {
+		req.Limit = h.defaultVerseLimit
+	}
+
+	citations, err := h.vectorSearch.SearchTopicVerses(ctx, topicID, req)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apiError(http.StatusNotFound, models.CodeTopicNotFound, "Topic not found")
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Topic search failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, citations)
+}
+
+// offsetParam reads the optional ?offset= query parameter, defaulting to 0
+func offsetParam(c echo.Context) (int, error) {
+	value := c.QueryParam("offset")
+	if value == "" {
+		return 0, nil
+	}
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, apiError(http.StatusBadRequest, models.CodeInvalidParameter, "offset must be a non-negative integer")
+	}
+	return offset, nil
+}
+
+// topicIDParam reads and validates the :id path parameter
+func topicIDParam(c echo.Context) (string, error) {
+	topicID := c.Param("id")
+	if _, err := strconv.Atoi(topicID); err != nil {
+		return "", apiError(http.StatusBadRequest, models.CodeInvalidTopicID, "Invalid topic id")
+	}
+	return topicID, nil
+}
+
+// RegisterRoutes registers topic routes, applying m to those that call the embedder
+func (h *TopicHandler) RegisterRoutes(g *echo.Group, m ...echo.MiddlewareFunc) {
+	g.GET("/topics", h.ListTopics)
+	g.GET("/topics/:id", h.GetTopic)
+	g.GET("/topics/:id/coverage", h.TopicCoverage)
+	g.GET("/topics/:id/related", h.RelatedTopics)
+	g.POST("/topics/:id/search", h.SearchTopicVerses, m...)
+}
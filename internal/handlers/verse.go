@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sola-scriptura-search-api/internal/config"
+	"github.com/sola-scriptura-search-api/internal/models"
+	"github.com/sola-scriptura-search-api/internal/repository"
+	"github.com/sola-scriptura-search-api/internal/services"
+)
+
+// VerseHandler handles direct verse lookup endpoints
+type VerseHandler struct {
+	vectorSearch      *services.VectorSearchService
+	defaultVerseLimit int // Similar verse limits, shared with search
+	maxVerseLimit     int
+}
+
+// NewVerseHandler creates a new verse handler
+func NewVerseHandler(vectorSearch *services.VectorSearchService) *VerseHandler {
+	cfg := config.GetConfig()
+	return &VerseHandler{
+		vectorSearch:      vectorSearch,
+		defaultVerseLimit: min(cfg.DefaultVerseLimit, cfg.MaxVerseLimit),
+		maxVerseLimit:     cfg.MaxVerseLimit,
+	}
+}
+
+// GetVerse handles GET /verses/:osisID - fetch a single verse by OSIS id (e.g. John.3.16)
+// or human reference (e.g. "John 3:16", URL-encoded)
+func (h *VerseHandler) GetVerse(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	verse, err := h.vectorSearch.GetVerse(ctx, c.Param("osisID"))
+	if errors.Is(err, services.ErrInvalidReference) {
+		return apiError(http.StatusBadRequest, models.CodeInvalidReference, "Invalid verse reference; expected e.g. John.3.16 or John 3:16")
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return apiError(http.StatusNotFound, models.CodeVerseNotFound, "Verse not found")
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Verse lookup failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, verse)
+}
+
+// BatchVerses handles POST /verses/batch - many verses by OSIS id or reference in one
+// call, in the requested order; unknown ids are omitted
+func (h *VerseHandler) BatchVerses(c echo.Context) error {
+	var req models.VerseBatchRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(http.StatusBadRequest, models.CodeInvalidBody, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	if len(req.IDs) > models.MaxBatchVerses {
+		return apiError(http.StatusBadRequest, models.CodeInvalidParameter, fmt.Sprintf("ids may list at most %d verses", models.MaxBatchVerses))
+	}
+
+	citations, err := h.vectorSearch.GetVerses(c.Request().Context(), req.IDs)
+	if errors.Is(err, services.ErrInvalidReference) {
+		return apiError(http.StatusBadRequest, models.CodeInvalidReference, err.Error())
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Verse lookup failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, citations)
+}
+
+// RandomVerse handles GET /verses/random?testament=NT&book=Ps - a single random verse
+func (h *VerseHandler) RandomVerse(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	testament := c.QueryParam("testament")
+	if testament != "" && testament != models.TestamentOld && testament != models.TestamentNew {
+		return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "testament must be \"OT\" or \"NT\" if set")
+	}
+
+	verse, err := h.vectorSearch.RandomVerse(ctx, testament, c.QueryParam("book"))
+	if errors.Is(err, services.ErrInvalidReference) {
+		return apiError(http.StatusBadRequest, models.CodeUnknownBook, "Unknown book; expected an OSIS id or name, e.g. Rom or Romans")
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return apiError(http.StatusNotFound, models.CodeVerseNotFound, "No verse matches the requested scope")
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Random verse lookup failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, verse)
+}
+
+// SimilarVerses handles GET /verses/:osisID/similar - verses closest in meaning to a verse
+func (h *VerseHandler) SimilarVerses(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limit, err := limitParam(c, h.defaultVerseLimit, h.maxVerseLimit)
+	if err != nil {
+		return err
+	}
+
+	citations, err := h.vectorSearch.SimilarVerses(ctx, c.Param("osisID"), limit)
+	if errors.Is(err, services.ErrInvalidReference) {
+		return apiError(http.StatusBadRequest, models.CodeInvalidReference, "Invalid verse reference; expected e.g. John.3.16 or John 3:16")
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return apiError(http.StatusNotFound, models.CodeVerseNotFound, "Verse not found")
+	}
+	if errors.Is(err, repository.ErrNoEmbedding) {
+		return apiError(http.StatusConflict, models.CodeNoEmbedding, "Verse has no embedding yet; run enrichment for it first")
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Similar verse search failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, citations)
+}
+
+// CrossReferences handles GET /verses/:osisID/cross-references - traditional cross-references
+func (h *VerseHandler) CrossReferences(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limit, err := limitParam(c, 10, 100)
+	if err != nil {
+		return err
+	}
+
+	citations, err := h.vectorSearch.CrossReferences(ctx, c.Param("osisID"), limit)
+	if errors.Is(err, services.ErrInvalidReference) {
+		return apiError(http.StatusBadRequest, models.CodeInvalidReference, "Invalid verse reference; expected e.g. John.3.16 or John 3:16")
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return apiError(http.StatusNotFound, models.CodeVerseNotFound, "Verse not found")
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Cross-reference lookup failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, citations)
+}
+
+// Topics handles GET /verses/:osisID/topics - curated topics the verse belongs to
+func (h *VerseHandler) Topics(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limit, err := limitParam(c, 20, 100)
+	if err != nil {
+		return err
+	}
+
+	topics, err := h.vectorSearch.VerseTopics(ctx, c.Param("osisID"), limit)
+	if errors.Is(err, services.ErrInvalidReference) {
+		return apiError(http.StatusBadRequest, models.CodeInvalidReference, "Invalid verse reference; expected e.g. John.3.16 or John 3:16")
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return apiError(http.StatusNotFound, models.CodeVerseNotFound, "Verse not found")
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Verse topic lookup failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, topics)
+}
+
+// Annotations handles GET /verses/:osisID/annotations - theological themes for a verse
+func (h *VerseHandler) Annotations(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	annotations, err := h.vectorSearch.VerseAnnotations(ctx, c.Param("osisID"))
+	if errors.Is(err, services.ErrInvalidReference) {
+		return apiError(http.StatusBadRequest, models.CodeInvalidReference, "Invalid verse reference; expected e.g. John.3.16 or John 3:16")
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return apiError(http.StatusNotFound, models.CodeVerseNotFound, "Verse not found")
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Annotation lookup failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, annotations)
+}
+
+// Compare handles GET /compare?a=John.3.16&b=Rom.5.8 - similarity of two verses' embeddings
+func (h *VerseHandler) Compare(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	a, b := c.QueryParam("a"), c.QueryParam("b")
+	if a == "" || b == "" {
+		return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "a and b are required")
+	}
+
+	// Errors name the offending verse, e.g. "John.3.16: verse has no embedding"
+	comparison, err := h.vectorSearch.CompareVerses(ctx, a, b)
+	if errors.Is(err, services.ErrInvalidReference) {
+		return apiError(http.StatusBadRequest, models.CodeInvalidReference, err.Error())
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return apiError(http.StatusNotFound, models.CodeVerseNotFound, err.Error())
+	}
+	if errors.Is(err, repository.ErrNoEmbedding) {
+		return apiError(http.StatusConflict, models.CodeNoEmbedding, err.Error())
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Verse comparison failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, comparison)
+}
+
+// GetPassage handles GET /passages?ref=Rom.8.28-Rom.8.30 - the ordered verses of a range
+// Accepts OSIS or human references; ranges may cross chapters but not books
+func (h *VerseHandler) GetPassage(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ref := c.QueryParam("ref")
+	if ref == "" {
+		return apiError(http.StatusBadRequest, models.CodeInvalidParameter, "ref is required")
+	}
+
+	citations, err := h.vectorSearch.GetPassage(ctx, ref)
+	if errors.Is(err, services.ErrInvalidReference) {
+		return apiError(http.StatusBadRequest, models.CodeInvalidReference, "Invalid passage reference; expected e.g. Rom.8.28-Rom.8.30 or Rom 8:28-30")
+	}
+	if errors.Is(err, services.ErrCrossBookRange) || errors.Is(err, services.ErrPassageTooLong) {
+		return apiError(http.StatusBadRequest, models.CodeInvalidRange, err.Error())
+	}
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Passage lookup failed: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, citations)
+}
+
+// ListBooks handles GET /books - the canon's books in order, with testament and genre
+func (h *VerseHandler) ListBooks(c echo.Context) error {
+	books, err := h.vectorSearch.ListBooks(c.Request().Context())
+	if err != nil {
+		return apiError(http.StatusInternalServerError, models.CodeBackendError, "Book listing failed: "+err.Error())
+	}
+	return c.JSON(http.StatusOK, books)
+}
+
+// limitParam reads the optional ?limit= query parameter, defaulting to def and capped at max
+func limitParam(c echo.Context, def, max int) (int, error) {
+	value := c.QueryParam("limit")
+	if value == "" {
+		return def, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return 0, apiError(http.StatusBadRequest, models.CodeInvalidParameter, "limit must be a positive integer")
+	}
+	return min(limit, max), nil
+}
+
+// RegisterRoutes registers verse routes
+func (h *VerseHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/verses/random", h.RandomVerse)
+	g.POST("/verses/batch", h.BatchVerses)
+	g.GET("/verses/:osisID", h.GetVerse)
+	g.GET("/verses/:osisID/similar", h.SimilarVerses)
+	g.GET("/verses/:osisID/cross-references", h.CrossReferences)
+	g.GET("/verses/:osisID/topics", h.Topics)
+	g.GET("/verses/:osisID/annotations", h.Annotations)
+	g.GET("/passages", h.GetPassage)
+	g.GET("/books", h.ListBooks)
+	g.GET("/compare", h.Compare)
+}
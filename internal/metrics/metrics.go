@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace prefixes every metric name
+const Namespace = "sola_scriptura"
+
+var (
+	// RequestsTotal counts HTTP requests by route and status code
+	RequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route and status code.",
+	}, []string{"endpoint", "method", "status"})
+
+	// RequestDuration observes HTTP request latency by route
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"endpoint", "method"})
+
+	// EmbeddingDuration observes embedding latency by task type ("query", including cache
+	// hits, or "document")
+	EmbeddingDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "embedding_duration_seconds",
+		Help:      "Embedding latency by task type and outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"task_type", "outcome"})
+
+	// EmbeddingCacheRequests counts query embedding cache lookups by result
+	EmbeddingCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "embedding_cache_requests_total",
+		Help:      "Query embedding cache lookups by result (hit or miss).",
+	}, []string{"result"})
+
+	// EmbedderReconnects counts Vertex AI prediction clients recreated after connection errors
+	EmbedderReconnects = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "embedder_reconnects_total",
+		Help:      "Vertex AI prediction clients recreated after a connection or credential error.",
+	})
+
+	// VectorSearchDuration observes nearest-neighbor search latency by backend
+	VectorSearchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "vector_search_duration_seconds",
+		Help:      "Vector search latency by backend and outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"backend", "outcome"})
+
+	// VectorFallbacks counts searches retried against the fallback vector backend
+	VectorFallbacks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "vector_search_fallbacks_total",
+		Help:      "Vector searches retried against the fallback backend after a retryable primary failure, by fallback and outcome.",
+	}, []string{"backend", "outcome"})
+
+	// MissingVerses counts neighbor ids the vector index returned that the verse view lacked
+	MissingVerses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "vector_missing_verses_total",
+		Help:      "Vector search neighbors missing from the verse view, by whether the base table had them.",
+	}, []string{"resolution"})
+
+	// TopicSearchDuration observes topic keyword search latency
+	TopicSearchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "topic_search_duration_seconds",
+		Help:      "Topic search latency by outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"outcome"})
+)
+
+// Register registers all metrics with reg
+func Register(reg prometheus.Registerer) {
+	reg.MustRegister(
+		RequestsTotal,
+		RequestDuration,
+		EmbeddingDuration,
+		EmbeddingCacheRequests,
+		EmbedderReconnects,
+		VectorSearchDuration,
+		VectorFallbacks,
+		TopicSearchDuration,
+		MissingVerses,
+	)
+}
+
+// Outcome labels a call as "ok" or "error"
+func Outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// Since returns the seconds elapsed since start, for Observe
+func Since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
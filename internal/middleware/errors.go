@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sola-scriptura-search-api/internal/models"
+)
+
+// statusCodes is the default code for errors raised without one (e.g. by Echo itself)
+var statusCodes = map[int]models.ErrorCode{
+	http.StatusBadRequest:          models.CodeInvalidParameter,
+	http.StatusNotFound:            models.CodeNotFound,
+	http.StatusMethodNotAllowed:    models.CodeMethodNotAllowed,
+	http.StatusUnprocessableEntity: models.CodeValidationFailed,
+	http.StatusTooManyRequests:     models.CodeRateLimited,
+}
+
+// HTTPErrorHandler writes every error as a models.ErrorResponse, keeping its status
+// Errors whose message is a models.ErrorDetail keep their code; string messages get a
+// default code for their status. Non-HTTP errors become a generic 500.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status := http.StatusInternalServerError
+	detail := models.ErrorDetail{Code: models.CodeInternal, Message: http.StatusText(status)}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		status = he.Code
+		switch m := he.Message.(type) {
+		case models.ErrorDetail:
+			detail = m
+		case string:
+			detail = models.ErrorDetail{Code: codeForStatus(status), Message: m}
+		default:
+			detail = models.ErrorDetail{Code: codeForStatus(status), Message: fmt.Sprint(m)}
+		}
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSON(status, models.ErrorResponse{Error: detail})
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
+
+// codeForStatus returns the default error code for an HTTP status
+func codeForStatus(status int) models.ErrorCode {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return models.CodeInternal
+	}
+	return models.CodeInvalidParameter
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sola-scriptura-search-api/internal/metrics"
+)
+
+// MetricsMiddleware records request counts and latency per route
+// Routes are labeled by their registered path (e.g. /api/v1/topics/:id), not the raw
+// URL, so label cardinality stays bounded
+func MetricsMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			status := responseStatus(c, err)
+			endpoint := c.Path()
+			if endpoint == "" {
+				endpoint = "unmatched"
+			}
+			method := c.Request().Method
+
+			metrics.RequestsTotal.WithLabelValues(endpoint, method, strconv.Itoa(status)).Inc()
+			metrics.RequestDuration.WithLabelValues(endpoint, method).Observe(metrics.Since(start))
+			return err
+		}
+	}
+}
+
+// responseStatus is the status a request will be answered with once err, if any,
+// reaches the HTTP error handler
+func responseStatus(c echo.Context, err error) int {
+	if err == nil {
+		return c.Response().Status
+	}
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/sola-scriptura-search-api/internal/config"
+	"github.com/sola-scriptura-search-api/internal/models"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitIdleExpiry is how long an idle client's bucket is kept
+const rateLimitIdleExpiry = 3 * time.Minute
+
+// RateLimitMiddleware returns a per-client token-bucket rate limiter
+// Clients are keyed by IP as resolved by the server's IPExtractor; API keys are not
+// authenticated, so keying by them would let a client dodge the limit with a fresh key
+// per request. Requests over the limit get 429 with a Retry-After header. A
+// non-positive rate disables limiting.
+func RateLimitMiddleware() echo.MiddlewareFunc {
+	cfg := config.GetConfig()
+
+	if cfg.RateLimitRPS <= 0 {
+		return func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	}
+
+	burst := cfg.RateLimitBurst
+	if burst < 1 {
+		burst = 1
+	}
+
+	// Seconds until the next token is available to a drained bucket
+	retryAfter := strconv.Itoa(int(math.Ceil(1 / cfg.RateLimitRPS)))
+
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+			Rate:      rate.Limit(cfg.RateLimitRPS),
+			Burst:     burst,
+			ExpiresIn: rateLimitIdleExpiry,
+		}),
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			return c.RealIP(), nil
+		},
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			c.Response().Header().Set("Retry-After", retryAfter)
+			return echo.NewHTTPError(http.StatusTooManyRequests, models.ErrorDetail{Code: models.CodeRateLimited, Message: "rate limit exceeded"})
+		},
+	})
+}
+
+// IPExtractor returns how client IPs are resolved for rate limiting and logs
+// With no trusted proxies the connection's remote address is used, so X-Forwarded-For
+// cannot be spoofed; otherwise X-Forwarded-For is honored through the given CIDR ranges
+// (plus loopback and private ranges)
+func IPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	options := make([]echo.TrustOption, 0, len(trustedProxies))
+	for _, cidr := range trustedProxies {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("parse trusted proxy %q: %w", cidr, err)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/sola-scriptura-search-api/internal/config"
+	"github.com/sola-scriptura-search-api/internal/models"
+)
+
+// Validator runs a struct's `validate` tags for echo.Context.Validate
+type Validator struct {
+	validate *validator.Validate
+}
+
+// LimitAliases returns the validate tag aliases for the configured result limits and
+// the search offset bound, e.g. `validate:"verse_limit"` expands to "min=1,max=<MAX_VERSE_LIMIT>"
+func LimitAliases(cfg *config.Config) map[string]string {
+	return map[string]string{
+		"verse_limit":   fmt.Sprintf("min=1,max=%d", cfg.MaxVerseLimit),
+		"topic_limit":   fmt.Sprintf("min=1,max=%d", cfg.MaxTopicLimit),
+		"search_offset": fmt.Sprintf("min=0,max=%d", models.MaxSearchOffset),
+	}
+}
+
+// NewValidator creates a validator that reports fields by their JSON names and
+// resolves the LimitAliases from the configuration
+func NewValidator() *Validator {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	for alias, rules := range LimitAliases(config.GetConfig()) {
+		v.RegisterAlias(alias, rules)
+	}
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return &Validator{validate: v}
+}
+
+// Validate checks i against its `validate` tags
+// Failures are returned as a 422 VALIDATION_FAILED error listing every offending field
+func (v *Validator) Validate(i any) error {
+	err := v.validate.Struct(i)
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+
+	fields := make([]models.FieldError, len(verrs))
+	messages := make([]string, len(verrs))
+	for n, fe := range verrs {
+		fields[n] = models.FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.ActualTag(),
+			Message: fieldMessage(fe),
+		}
+		messages[n] = fields[n].Message
+	}
+	return echo.NewHTTPError(http.StatusUnprocessableEntity, models.ErrorDetail{
+		Code:    models.CodeValidationFailed,
+		Message: "Invalid request: " + strings.Join(messages, "; "),
+		Fields:  fields,
+	})
+}
+
+// fieldMessage renders a human-readable message for a failed rule
+// Aliases are reported by the rule they expand to, e.g. "max" for verse_limit
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.ActualTag() {
+	case "required":
+		return fe.Field() + " is required"
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at least %s characters", fe.Field(), fe.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s", fe.Field(), fe.Param())
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at most %s characters", fe.Field(), fe.Param())
+		}
+		return fmt.Sprintf("%s must be at most %s", fe.Field(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", fe.Field(), strings.ReplaceAll(fe.Param(), " ", ", "))
+	default:
+		return fmt.Sprintf("%s failed %s validation", fe.Field(), fe.ActualTag())
+	}
+}
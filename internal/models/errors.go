@@ -0,0 +1,48 @@
+package models
+
+// ErrorCode is a stable, machine-readable identifier for an API error
+// Clients branch on the code; messages are for humans and may change
+type ErrorCode string
+
+// Error codes returned in ErrorResponse
+const (
+	// Request validation
+	CodeInvalidBody      ErrorCode = "INVALID_BODY"      // Request body is not valid JSON for the endpoint
+	CodeInvalidParameter ErrorCode = "INVALID_PARAMETER" // A body field or query parameter is out of range
+	CodeValidationFailed ErrorCode = "VALIDATION_FAILED" // A body field breaks its validate tag; see ErrorDetail.Fields
+	CodeInvalidReference ErrorCode = "INVALID_REFERENCE" // Malformed verse or passage reference
+	CodeInvalidRange     ErrorCode = "INVALID_RANGE"     // Passage spans books or is too long
+	CodeUnknownBook      ErrorCode = "UNKNOWN_BOOK"
+	CodeInvalidTopicID   ErrorCode = "INVALID_TOPIC_ID"
+
+	// Missing data
+	CodeNotFound      ErrorCode = "NOT_FOUND" // Unknown route or resource
+	CodeVerseNotFound ErrorCode = "VERSE_NOT_FOUND"
+	CodeTopicNotFound ErrorCode = "TOPIC_NOT_FOUND"
+	CodeNoEmbedding   ErrorCode = "NO_EMBEDDING" // Verse exists but has not been embedded
+
+	// Server side
+	CodeRateLimited      ErrorCode = "RATE_LIMITED"
+	CodeMethodNotAllowed ErrorCode = "METHOD_NOT_ALLOWED"
+	CodeBackendError     ErrorCode = "BACKEND_ERROR" // Database, vector index or embedder failure
+	CodeInternal         ErrorCode = "INTERNAL_ERROR"
+)
+
+// ErrorDetail is the body of an API error
+type ErrorDetail struct {
+	Code    ErrorCode    `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"` // Only set for VALIDATION_FAILED
+}
+
+// FieldError describes one request body field that failed validation
+type FieldError struct {
+	Field   string `json:"field"` // JSON name of the field, e.g. "limit"
+	Rule    string `json:"rule"`  // Failed validate rule, e.g. "max"
+	Message string `json:"message"`
+}
+
+// ErrorResponse is the envelope every error response uses
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
@@ -2,12 +2,153 @@ package models
 
 // Citation represents a cited verse with relevance score
 type Citation struct {
-	VerseID        string   `json:"verse_id" db:"verse_id"`
-	Text           string   `json:"text" db:"text"`
-	Book           string   `json:"book" db:"book"`
-	Chapter        int      `json:"chapter" db:"chapter"`
-	Verse          int      `json:"verse" db:"verse"`
-	RelevanceScore *float64 `json:"relevance_score,omitempty" db:"relevance_score"`
+	VerseID        string     `json:"verse_id" db:"verse_id"`
+	Text           string     `json:"text" db:"text"`
+	Book           string     `json:"book" db:"book"`
+	Chapter        int        `json:"chapter" db:"chapter"`
+	Verse          int        `json:"verse" db:"verse"`
+	EndVerse       int        `json:"end_verse,omitempty" db:"-"` // Last verse when adjacent hits are grouped into a passage
+	RelevanceScore *float64   `json:"relevance_score,omitempty" db:"relevance_score"`
+	ImportanceTier *int       `json:"importance_tier,omitempty" db:"importance_tier"` // Topic verses only: 1=essential, 2=important, 3=supporting
+	Pericope       *Pericope  `json:"pericope,omitempty" db:"-"`
+	Context        []Citation `json:"context,omitempty" db:"-"`      // The verse with its neighbors when context_window is set
+	CrossRefs      []Citation `json:"cross_refs,omitempty" db:"-"`   // Cross-reference targets when include_cross_refs is set
+	FusionScore    *float64   `json:"fusion_score,omitempty" db:"-"` // Reciprocal rank fusion score when hybrid fusion or lexical is enabled
+
+	Explain *ScoreExplanation `json:"explain,omitempty" db:"-"` // Scoring internals with ?explain=true
+}
+
+// ScoreExplanation shows how a citation was scored, for tuning and debugging
+type ScoreExplanation struct {
+	DistanceMeasure string               `json:"distance_measure,omitempty"` // Backend measure the distance is in
+	Distance        *float64             `json:"distance,omitempty"`         // Raw vector backend distance
+	Similarity      *float64             `json:"similarity,omitempty"`       // Distance normalized to [0, 1]
+	TextRank        *float64             `json:"text_rank,omitempty"`        // Full-text ts_rank (keyword and lexical hits)
+	Fusion          []FusionContribution `json:"fusion,omitempty"`           // Per-list RRF contributions summing to fusion_score
+}
+
+// Reciprocal rank fusion input lists
+const (
+	FusionListSemantic = "semantic"
+	FusionListTopic    = "topic"
+	FusionListLexical  = "lexical"
+)
+
+// FusionContribution is one ranked list's share of a fused score: weight / (60 + rank)
+type FusionContribution struct {
+	List   string  `json:"list"` // One of the FusionList names
+	Rank   int     `json:"rank"` // 1-based position in the list
+	Weight float64 `json:"weight"`
+	Score  float64 `json:"score"`
+}
+
+// Pericope is the coherent passage (paragraph/thought unit) containing a verse
+type Pericope struct {
+	Title        string     `json:"title,omitempty"`
+	StartVerseID string     `json:"start_verse_id"`
+	EndVerseID   string     `json:"end_verse_id"`
+	Verses       []Citation `json:"verses"`
+	Fallback     bool       `json:"fallback,omitempty"` // No boundary data; Verses is a fixed window around the verse
+}
+
+// VerseFilter scopes verse retrieval to a subset of the canon
+// An empty field places no restriction on that dimension
+type VerseFilter struct {
+	Books      []string // OSIS book ids, e.g. "Rom"
+	Testaments []string // "OT" and/or "NT"
+
+	// Inclusive canonical book_order range (1 = Genesis, 66 = Revelation; 0 = unbounded)
+	MinBookOrder int
+	MaxBookOrder int
+}
+
+// IsEmpty reports whether the filter places no restriction at all
+func (f VerseFilter) IsEmpty() bool {
+	return len(f.Books) == 0 && len(f.Testaments) == 0 && f.MinBookOrder == 0 && f.MaxBookOrder == 0
+}
+
+// InBookOrderRange reports whether a book position is within the filter's range
+func (f VerseFilter) InBookOrderRange(bookOrder int) bool {
+	return (f.MinBookOrder == 0 || bookOrder >= f.MinBookOrder) &&
+		(f.MaxBookOrder == 0 || bookOrder <= f.MaxBookOrder)
+}
+
+// VerseRange is an inclusive span of verses within one book
+type VerseRange struct {
+	Book         string // OSIS book id
+	StartChapter int
+	StartVerse   int // 0 = from the start of StartChapter
+	EndChapter   int
+	EndVerse     int // 0 = through the end of EndChapter
+}
+
+// MaxPassageVerses bounds how many verses a single passage request may return
+const MaxPassageVerses = 1000
+
+// MaxBatchVerses bounds how many ids a single verse batch request may list
+const MaxBatchVerses = 200
+
+// VerseBatchRequest is the request for fetching many verses by reference in one call
+type VerseBatchRequest struct {
+	IDs []string `json:"ids" validate:"required"` // OSIS ids or human references, at most MaxBatchVerses
+}
+
+// VerseAnnotation is a theological theme label assigned to a verse by enrichment
+type VerseAnnotation struct {
+	Annotation string `json:"annotation" db:"annotation"`
+	Source     string `json:"source" db:"source"`
+}
+
+// VerseAnnotations is the response for a verse's annotations
+type VerseAnnotations struct {
+	VerseID     string            `json:"verse_id"`
+	Annotations []VerseAnnotation `json:"annotations"`
+}
+
+// Embedding task types accepted by POST /embed
+const (
+	EmbedTaskQuery    = "query"
+	EmbedTaskDocument = "document"
+)
+
+// MaxEmbedTextLength bounds the characters POST /embed accepts per request
+const MaxEmbedTextLength = 8000
+
+// EmbedRequest is the request for a raw embedding of arbitrary text
+type EmbedRequest struct {
+	Text     string `json:"text" validate:"required,max=8000"`                             // At most MaxEmbedTextLength characters
+	TaskType string `json:"task_type,omitempty" validate:"omitempty,oneof=query document"` // "query" (default) or "document"
+}
+
+// EmbedResponse is the embedding of an EmbedRequest's text
+type EmbedResponse struct {
+	Embedding  []float64 `json:"embedding"`
+	Dimensions int       `json:"dimensions"`
+	TaskType   string    `json:"task_type"`
+}
+
+// VerseComparison is the semantic similarity of two verses' stored embeddings
+type VerseComparison struct {
+	A          Citation `json:"a"`
+	B          Citation `json:"b"`
+	Similarity float64  `json:"similarity"` // Cosine similarity, -1 to 1
+}
+
+// QueryTerm is a tokenized query word with its scoring weight (1 = unweighted)
+type QueryTerm struct {
+	Word   string
+	Weight float64
+	Source string // Query word or phrase a stem or synonym was expanded from ("" = Word itself)
+}
+
+// BookInfo is a canonical book's metadata, for building and validating filters
+type BookInfo struct {
+	OSISID    string `json:"osis_id" db:"osis_id"`
+	Name      string `json:"name" db:"-"`
+	Testament string `json:"testament" db:"testament"`
+	BookOrder int    `json:"book_order" db:"book_order"`
+	Genre     string `json:"genre,omitempty" db:"genre"` // e.g. law, history, wisdom, gospels
+	Chapters  int    `json:"chapters" db:"-"`
 }
 
 // ScoredVerse represents a verse with similarity score
@@ -18,18 +159,38 @@ type ScoredVerse struct {
 	Verse   int     `json:"verse"`
 	Text    string  `json:"text"`
 	Score   float64 `json:"score"`
+
+	BookOrder int       `json:"-"` // Canonical book position, used to break score ties
+	Embedding []float64 `json:"-"` // Stored verse embedding, only set when requested
+
+	// Raw backend distance Score was normalized from (vector backends only)
+	DistanceMeasure string  `json:"-"`
+	Distance        float64 `json:"-"`
+}
+
+// VectorSearchOptions control a single vector backend query
+type VectorSearchOptions struct {
+	TopK              int
+	Offset            int // Skip this many nearest neighbors
+	Filter            VerseFilter
+	IncludeEmbeddings bool // Return each result's stored embedding (for re-ranking)
+	MaxPerBook        int  // Return at most this many neighbors per book (0 = no cap)
 }
 
 // ScoredTopic represents a topic with relevance score
 type ScoredTopic struct {
-	TopicID      string   `json:"topic_id"`
-	Name         string   `json:"name"`
-	Source       string   `json:"source"`
-	Category     string   `json:"category,omitempty"`
-	ChapterRefs  []string `json:"chapter_refs,omitempty"`
-	VerseCount   int      `json:"verse_count"`
-	Score        float64  `json:"score"`
-	MatchedWords []string `json:"matched_words,omitempty"`
+	TopicID      string    `json:"topic_id"`
+	Name         string    `json:"name"`
+	Source       string    `json:"source"`
+	Category     string    `json:"category,omitempty"`
+	ChapterRefs  []string  `json:"chapter_refs,omitempty"`
+	VerseCount   int       `json:"verse_count"`
+	Score        float64   `json:"score"`
+	MatchedWords []string  `json:"matched_words,omitempty"`
+	TopVerse     *Citation `json:"top_verse,omitempty"` // Highest-tier verse, when requested
+
+	// Sources of same-named topics collapsed into this one (see TopicSearchOptions.AllSources)
+	MergedSources []string `json:"merged_sources,omitempty"`
 }
 
 // Topic represents a topical index entry
@@ -41,31 +202,302 @@ type Topic struct {
 	ChapterRefs []string `json:"chapter_refs,omitempty"`
 }
 
+// TopicSummary is a topic as listed in the topic directory
+type TopicSummary struct {
+	TopicID    string `json:"topic_id" db:"topic_id"`
+	Name       string `json:"name" db:"name"`
+	Source     string `json:"source" db:"source"`
+	Category   string `json:"category,omitempty" db:"category"`
+	VerseCount int    `json:"verse_count" db:"verse_count"`
+}
+
+// VerseTopic is a topic a verse is mapped to, with the verse's tier within it
+type VerseTopic struct {
+	TopicSummary
+	ImportanceTier int `json:"importance_tier" db:"importance_tier"` // 1=essential, 2=important, 3=supporting
+}
+
+// Topic list sort orders
+const (
+	TopicSortName       = "name"        // Alphabetical (default)
+	TopicSortVerseCount = "verse_count" // Most verses first
+	TopicSortSource     = "source"      // Grouped by source, then alphabetical
+)
+
+// TopicListOptions select and order a page of topics
+type TopicListOptions struct {
+	Limit    int
+	Offset   int
+	Sort     string // One of the TopicSort constants
+	Category string // Only topics in this category (empty = all)
+	Source   string // Only topics from this source (empty = all)
+}
+
+// TopicListResponse is a page of topics with the total matching count
+type TopicListResponse struct {
+	Topics []TopicSummary `json:"topics"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// TopicDetail is a single topic with its metadata and mapped verses
+// Unknown topic ids are a 404 TOPIC_NOT_FOUND; an existing topic with no verses matching
+// the filters (or past the last page) has an empty verses array and a non-zero verse_count
+// if it maps any verses at all
+type TopicDetail struct {
+	TopicID     string     `json:"topic_id" db:"topic_id"`
+	Name        string     `json:"name" db:"name"`
+	Slug        string     `json:"slug,omitempty" db:"slug"`
+	Source      string     `json:"source" db:"source"`
+	Category    string     `json:"category,omitempty" db:"category"`
+	Description string     `json:"description,omitempty" db:"description"`
+	VerseCount  int        `json:"verse_count" db:"verse_count"`
+	Verses      []Citation `json:"verses" db:"-"` // Ordered by importance tier, then canonically
+
+	// Pagination of verses: has_more is true when another page exists at offset+limit
+	Limit   int  `json:"limit" db:"-"`
+	Offset  int  `json:"offset" db:"-"`
+	HasMore bool `json:"has_more" db:"-"`
+}
+
 // TopicSearchResult wraps a topic with search score
 type TopicSearchResult struct {
-	Topic      Topic   `json:"topic"`
-	Score      float64 `json:"score"`
-	VerseCount int     `json:"verse_count"`
-	Category   string  `json:"category,omitempty"`
+	Topic        Topic    `json:"topic"`
+	Score        float64  `json:"score"`
+	VerseCount   int      `json:"verse_count"`
+	Category     string   `json:"category,omitempty"`
+	MatchedWords []string `json:"matched_words,omitempty"` // Query words found in topic, sub_topic or name
+}
+
+// VerseSearchOptions are optional verse filters shared by the search requests
+// Text length filters run after retrieval; the backend is over-fetched to
+// compensate, but a heavily filtered query may still return fewer than limit results
+type VerseSearchOptions struct {
+	MinTextLength int `json:"min_text_length,omitempty" validate:"min=0"` // Minimum verse length in characters (0 = no minimum)
+	MaxTextLength int `json:"max_text_length,omitempty" validate:"min=0"` // Maximum verse length in characters (0 = no maximum)
+
+	// Expand set to "pericope" attaches the full passage containing each result
+	Expand string `json:"expand,omitempty" validate:"omitempty,oneof=pericope"`
+
+	// Books restricts results to these OSIS book ids, e.g. ["Rom", "Gal"] (empty = all)
+	Books []string `json:"books,omitempty"`
+	// Testament restricts results to "OT" or "NT" (empty = both)
+	Testament string `json:"testament,omitempty" validate:"omitempty,oneof=OT NT"`
+
+	// MinBookOrder/MaxBookOrder restrict results to an inclusive range of canonical book
+	// positions (1-66, 0 = unbounded); BookRange names a common range instead
+	MinBookOrder int    `json:"min_book_order,omitempty" validate:"min=0,max=66"`
+	MaxBookOrder int    `json:"max_book_order,omitempty" validate:"min=0,max=66"`
+	BookRange    string `json:"book_range,omitempty"` // One of the BookRanges keys
+
+	// MinScore drops semantic results below this cosine similarity (0-1, 0 = keep all)
+	MinScore float64 `json:"min_score,omitempty" validate:"min=0,max=1"`
+
+	// ContextWindow attaches up to this many verses either side of each result from
+	// the same chapter (0 = none, capped at MaxContextWindow)
+	ContextWindow int `json:"context_window,omitempty" validate:"min=0"`
+
+	// GroupAdjacent merges consecutive verses of the same chapter into one passage
+	// citation spanning verse..end_verse, scored by its best member
+	GroupAdjacent bool `json:"group_adjacent,omitempty"`
+
+	// Diversity trades relevance for variety via MMR re-ranking
+	// (0 = pure relevance, 1 = maximum diversity)
+	Diversity float64 `json:"diversity,omitempty" validate:"min=0,max=1"`
+
+	// IncludeCrossRefs attaches up to CrossRefsPerResult cross-references to each result
+	IncludeCrossRefs bool `json:"include_cross_refs,omitempty"`
+
+	// Offset skips this many ranked results for pagination (max MaxSearchOffset)
+	Offset int `json:"offset,omitempty" validate:"search_offset"`
+
+	// MaxPerBook caps semantic results from any one book so a single chapter cannot
+	// crowd out the rest of the canon (0 = no cap)
+	MaxPerBook int `json:"max_per_book,omitempty" validate:"min=0"`
+
+	// Explain attaches scoring internals to each result (set from ?explain=true)
+	Explain bool `json:"-"`
+}
+
+// CrossRefsPerResult caps the cross-references attached to each result
+const CrossRefsPerResult = 5
+
+// MaxSearchOffset bounds VerseSearchOptions.Offset to protect the vector index
+const MaxSearchOffset = 500
+
+// MaxContextWindow caps VerseSearchOptions.ContextWindow to keep payloads small
+const MaxContextWindow = 5
+
+// VerseFilter returns the retrieval-time scope requested by the options
+// A named BookRange takes precedence over explicit book order bounds
+func (o VerseSearchOptions) VerseFilter() VerseFilter {
+	filter := VerseFilter{Books: o.Books, MinBookOrder: o.MinBookOrder, MaxBookOrder: o.MaxBookOrder}
+	if o.Testament != "" {
+		filter.Testaments = []string{o.Testament}
+	}
+	if r, ok := BookRanges[o.BookRange]; ok {
+		filter.MinBookOrder, filter.MaxBookOrder = r[0], r[1]
+	}
+	return filter
+}
+
+// BookCount is the number of books in the canon, the largest book_order
+const BookCount = 66
+
+// BookRanges maps VerseSearchOptions.BookRange names to inclusive book_order bounds
+var BookRanges = map[string][2]int{
+	"pentateuch":       {1, 5},   // Genesis-Deuteronomy
+	"history":          {6, 17},  // Joshua-Esther
+	"wisdom":           {18, 22}, // Job-Song of Songs
+	"major_prophets":   {23, 27}, // Isaiah-Daniel
+	"minor_prophets":   {28, 39}, // Hosea-Malachi
+	"gospels":          {40, 43}, // Matthew-John
+	"pauline":          {45, 57}, // Romans-Philemon
+	"general_epistles": {58, 65}, // Hebrews-Jude
 }
 
-// SemanticSearchRequest is the request for semantic search
-type SemanticSearchRequest struct {
+// Testaments accepted by VerseSearchOptions.Testament
+const (
+	TestamentOld = "OT"
+	TestamentNew = "NT"
+)
+
+// ExpandPericope is the VerseSearchOptions.Expand value for passage expansion
+const ExpandPericope = "pericope"
+
+// TopicSearchOptions are optional topic search parameters
+type TopicSearchOptions struct {
+	IncludeTopVerse bool `json:"include_top_verse,omitempty"`               // Attach each topic's top (tier-1 first) verse as a preview
+	MaxTier         int  `json:"max_tier,omitempty" validate:"min=0,max=3"` // Topic cards only include verses at or above this tier (1 = essentials, 0 = all)
+	AllSources      bool `json:"all_sources,omitempty"`                     // Keep same-named topics from every source instead of the preferred one
+}
+
+// HybridSearchOptions are optional parameters that only apply to hybrid search
+type HybridSearchOptions struct {
+	// Fusion merges semantic hits and matched topics' verses into one list ranked by
+	// reciprocal rank fusion, replacing the semantic-only verse list
+	Fusion bool `json:"fusion,omitempty"`
+
+	// Lexical also runs a full-text search over verse text and fuses its ranking with
+	// the semantic hits by reciprocal rank fusion, helping rare proper nouns (e.g.
+	// "Melchizedek") that embeddings match poorly. Like Fusion it does not paginate.
+	Lexical bool `json:"lexical,omitempty"`
+
+	// Relative influence of semantic rank, topic membership and lexical rank on fused
+	// ranking. Non-negative and normalized to sum to 1 over the enabled lists; unset uses
+	// DefaultVerseWeight/DefaultTopicWeight/DefaultLexicalWeight
+	VerseWeight   *float64 `json:"verse_weight,omitempty" validate:"omitempty,min=0"`
+	TopicWeight   *float64 `json:"topic_weight,omitempty" validate:"omitempty,min=0"`
+	LexicalWeight *float64 `json:"lexical_weight,omitempty" validate:"omitempty,min=0"`
+}
+
+// Default hybrid fusion weights
+const (
+	DefaultVerseWeight   = 0.7
+	DefaultTopicWeight   = 0.3
+	DefaultLexicalWeight = 0.3
+)
+
+// FusionWeights returns the normalized verse, topic and lexical weights, applying
+// defaults. Topic and lexical weights are 0 unless Fusion and Lexical are set.
+// Callers must have validated that the weights are non-negative and not all zero
+func (o HybridSearchOptions) FusionWeights() (verseWeight, topicWeight, lexicalWeight float64) {
+	verseWeight = DefaultVerseWeight
+	if o.VerseWeight != nil {
+		verseWeight = *o.VerseWeight
+	}
+	if o.Fusion {
+		topicWeight = DefaultTopicWeight
+		if o.TopicWeight != nil {
+			topicWeight = *o.TopicWeight
+		}
+	}
+	if o.Lexical {
+		lexicalWeight = DefaultLexicalWeight
+		if o.LexicalWeight != nil {
+			lexicalWeight = *o.LexicalWeight
+		}
+	}
+	total := verseWeight + topicWeight + lexicalWeight
+	return verseWeight / total, topicWeight / total, lexicalWeight / total
+}
+
+// TopicVerseOptions select which of a topic's mapped verses are returned
+type TopicVerseOptions struct {
+	Limit   int
+	Offset  int // Skip this many verses in importance order
+	MaxTier int // Only verses with importance_tier <= MaxTier (0 = all tiers)
+	Filter  VerseFilter
+}
+
+// Search modes accepted by POST /search
+const (
+	SearchModeSemantic  = "semantic"  // Embedding similarity over verses (default)
+	SearchModeKeyword   = "keyword"   // Full-text search over verse text
+	SearchModeHybrid    = "hybrid"    // Semantic verses plus keyword-matched topics and topic cards
+	SearchModeReference = "reference" // Direct lookup of OSIS verse ids, e.g. "John.3.16, Rom.5.8"
+)
+
+// SearchModes lists the valid search modes
+var SearchModes = []string{SearchModeSemantic, SearchModeKeyword, SearchModeHybrid, SearchModeReference}
+
+// SearchRequest is the request for unified search
+type SearchRequest struct {
+	Query      string `json:"query" validate:"required"`
+	Mode       string `json:"mode,omitempty" validate:"omitempty,oneof=semantic keyword hybrid reference"` // One of SearchModes; empty = semantic
+	Limit      int    `json:"limit" validate:"omitempty,verse_limit"`                                      // Default DEFAULT_VERSE_LIMIT
+	TopicLimit int    `json:"topic_limit,omitempty" validate:"omitempty,topic_limit"`                      // Hybrid only; default DEFAULT_TOPIC_LIMIT
+	VerseSearchOptions
+	TopicSearchOptions
+	HybridSearchOptions
+}
+
+// SearchResponse is the response envelope for every search mode
+// All modes set mode, query and results (verse citations). Semantic and keyword results
+// carry relevance_score; reference results do not and keep the order they were requested
+// in. Only hybrid sets topics, topic_card and topic_cards.
+type SearchResponse struct {
+	Mode       string        `json:"mode"`
+	Query      string        `json:"query"`
+	Results    []Citation    `json:"results"`
+	Topics     []ScoredTopic `json:"topics,omitempty"`
+	TopicCard  *TopicCard    `json:"topic_card,omitempty"`
+	TopicCards []TopicCard   `json:"topic_cards,omitempty"` // One per distinct concept; TopicCard is the first
+
+	// Pagination of results: has_more is true when another page exists at offset+limit
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"has_more"`
+}
+
+// TextSearchRequest is the request for phrase search over verse text
+// By default the query must appear as a phrase after English stemming, so "a time to
+// every purpose" also matches "a time to every purposes"; Exact instead requires the
+// literal text (case-insensitive substring, punctuation included)
+type TextSearchRequest struct {
 	Query string `json:"query" validate:"required"`
-	Limit int    `json:"limit" validate:"min=1,max=50"`
+	Exact bool   `json:"exact,omitempty"`
+	Limit int    `json:"limit" validate:"omitempty,verse_limit"` // Default DEFAULT_VERSE_LIMIT
+	VerseSearchOptions
 }
 
-// SemanticSearchResponse is the response for semantic search
-type SemanticSearchResponse struct {
-	Query   string     `json:"query"`
-	Results []Citation `json:"results"`
+// TopicVerseSearchRequest is the request for semantic search within one topic's verses
+type TopicVerseSearchRequest struct {
+	Query    string  `json:"query" validate:"required"`
+	Limit    int     `json:"limit" validate:"omitempty,verse_limit"`     // Default DEFAULT_VERSE_LIMIT
+	MaxTier  int     `json:"max_tier,omitempty" validate:"min=0,max=3"`  // Only search verses at or above this tier (0 = all)
+	MinScore float64 `json:"min_score,omitempty" validate:"min=0,max=1"` // Drop verses less similar than this
 }
 
 // HybridSearchRequest is the request for hybrid search
 type HybridSearchRequest struct {
 	Query      string `json:"query" validate:"required"`
-	VerseLimit int    `json:"verse_limit" validate:"min=1,max=50"`
-	TopicLimit int    `json:"topic_limit" validate:"min=1,max=50"`
+	VerseLimit int    `json:"verse_limit" validate:"omitempty,verse_limit"`
+	TopicLimit int    `json:"topic_limit" validate:"omitempty,topic_limit"`
+	VerseSearchOptions
+	TopicSearchOptions
+	HybridSearchOptions
 }
 
 // ResourceMatches contains results from curated sources
@@ -80,19 +512,60 @@ type SemanticMatches struct {
 
 // TopicCard represents a featured topic with its key verses
 type TopicCard struct {
-	TopicID    string     `json:"topic_id"`
-	Name       string     `json:"name"`
-	Category   string     `json:"category,omitempty"`
-	Source     string     `json:"source,omitempty"`
-	VerseCount int        `json:"verse_count"`
-	Score      float64    `json:"score"`
-	TopVerses  []Citation `json:"top_verses"`
+	TopicID     string     `json:"topic_id"`
+	Name        string     `json:"name"`
+	Category    string     `json:"category,omitempty"`
+	Source      string     `json:"source,omitempty"`
+	VerseCount  int        `json:"verse_count"`
+	Score       float64    `json:"score"`
+	ChapterRefs []string   `json:"chapter_refs,omitempty"` // Chapters holding most of the topic's verses
+	TopVerses   []Citation `json:"top_verses"`
+}
+
+// TierCoverage reports how well one importance tier of a topic is covered
+// Expected and Present are only set when a canonical definition is registered
+type TierCoverage struct {
+	Tier     int  `json:"tier"`
+	Mapped   int  `json:"mapped"`
+	Expected *int `json:"expected,omitempty"`
+	Present  *int `json:"present,omitempty"`
+}
+
+// Topic relations to a topic in the hierarchy
+const (
+	TopicRelationParent  = "parent"
+	TopicRelationSibling = "sibling"
+	TopicRelationChild   = "child"
+)
+
+// RelatedTopic is a topic near another in the hierarchy
+type RelatedTopic struct {
+	TopicSummary
+	Relation string `json:"-" db:"relation"`
+}
+
+// RelatedTopics is a topic's neighbourhood in the hierarchy, where a topic's sub_topic
+// names its parent within the same source (e.g. Justification under Salvation)
+type RelatedTopics struct {
+	TopicID  string         `json:"topic_id"`
+	Parent   *TopicSummary  `json:"parent,omitempty"`
+	Siblings []TopicSummary `json:"siblings"` // Other children of the parent, alphabetical
+	Children []TopicSummary `json:"children"` // Alphabetical
+}
+
+// TopicCoverage reports mapped versus canonical verse counts per importance tier
+type TopicCoverage struct {
+	TopicID          string         `json:"topic_id"`
+	HasCanonical     bool           `json:"has_canonical"`
+	Tiers            []TierCoverage `json:"tiers"`
+	MissingEssential []string       `json:"missing_essential"`
 }
 
 // HybridSearchResponse is the response for hybrid search
 type HybridSearchResponse struct {
 	Query           string          `json:"query"`
 	TopicCard       *TopicCard      `json:"topic_card,omitempty"`
+	TopicCards      []TopicCard     `json:"topic_cards,omitempty"` // One per distinct concept; TopicCard is the first
 	ResourceMatches ResourceMatches `json:"resource_matches"`
 	SemanticMatches SemanticMatches `json:"semantic_matches"`
 }
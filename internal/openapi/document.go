@@ -0,0 +1,187 @@
+package openapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Document is an OpenAPI 3 document
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Servers    []Server                        `json:"servers,omitempty"`
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components components                      `json:"components"`
+
+	schemas *schemaBuilder
+}
+
+// Info describes the API
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Server is a base URL the paths are relative to
+type Server struct {
+	URL string `json:"url"`
+}
+
+type components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+type operation struct {
+	Summary     string              `json:"summary,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []parameter         `json:"parameters,omitempty"`
+	RequestBody *requestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]response `json:"responses"`
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Param is a query parameter of an operation
+type Param struct {
+	Name string
+	Type string // "string", "integer", "number" or "boolean"
+	Enum []string
+}
+
+// Operation describes one route for Add
+// Request and Response are zero values of the Go types marshaled as JSON; their
+// schemas are derived from the json and validate struct tags
+type Operation struct {
+	Method   string // e.g. "GET"
+	Path     string // Echo-style, e.g. /topics/:id
+	Summary  string
+	Tag      string
+	Query    []Param
+	Request  any
+	Response any
+	Errors   []int // Additional error status codes, e.g. 400, 404
+
+	PlainText   bool // The response can also be rendered as text/plain via Accept
+	EventStream bool // The response is a text/event-stream whose events carry Response
+}
+
+// NewDocument creates an empty document served under serverURL
+func NewDocument(info Info, serverURL string) *Document {
+	d := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]map[string]operation),
+		schemas: newSchemaBuilder(),
+	}
+	if serverURL != "" {
+		d.Servers = []Server{{URL: serverURL}}
+	}
+	d.Components.Schemas = d.schemas.components
+	// Matches the {"error": {"code", "message", "fields"}} envelope of every error response
+	d.Components.Schemas["Error"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{"error": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"code":    {Type: "string"},
+				"message": {Type: "string"},
+				"fields": {Type: "array", Items: &Schema{
+					Type:       "object",
+					Properties: map[string]*Schema{"field": {Type: "string"}, "rule": {Type: "string"}, "message": {Type: "string"}},
+					Required:   []string{"field", "rule", "message"},
+				}},
+			},
+			Required: []string{"code", "message"},
+		}},
+		Required: []string{"error"},
+	}
+	return d
+}
+
+// SetValidateAliases expands custom `validate` tag aliases (alias -> rules) in request
+// schemas; call it before Add so the bounds match the validator's
+func (d *Document) SetValidateAliases(aliases map[string]string) {
+	d.schemas.aliases = aliases
+}
+
+// Add registers an operation, deriving its path parameters from :name segments
+func (d *Document) Add(op Operation) {
+	path, params := convertPath(op.Path)
+	for _, q := range op.Query {
+		params = append(params, parameter{
+			Name:   q.Name,
+			In:     "query",
+			Schema: &Schema{Type: q.Type, Enum: q.Enum},
+		})
+	}
+
+	o := operation{
+		Summary:    op.Summary,
+		Parameters: params,
+		Responses:  make(map[string]response),
+	}
+	if op.Tag != "" {
+		o.Tags = []string{op.Tag}
+	}
+	if op.Request != nil {
+		o.RequestBody = &requestBody{
+			Required: true,
+			Content:  map[string]mediaType{"application/json": {Schema: d.schemas.ref(op.Request)}},
+		}
+	}
+	o.Responses["200"] = response{
+		Description: "OK",
+		Content:     map[string]mediaType{"application/json": {Schema: d.schemas.ref(op.Response)}},
+	}
+	if op.EventStream {
+		o.Responses["200"] = response{
+			Description: "OK",
+			Content:     map[string]mediaType{"text/event-stream": {Schema: d.schemas.ref(op.Response)}},
+		}
+	}
+	if op.PlainText {
+		o.Responses["200"].Content["text/plain"] = mediaType{Schema: &Schema{Type: "string"}}
+	}
+	errorContent := map[string]mediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}}}
+	for _, code := range op.Errors {
+		o.Responses[strconv.Itoa(code)] = response{Description: http.StatusText(code), Content: errorContent}
+	}
+
+	if d.Paths[path] == nil {
+		d.Paths[path] = make(map[string]operation)
+	}
+	d.Paths[path][strings.ToLower(op.Method)] = o
+}
+
+// convertPath rewrites /topics/:id to /topics/{id} and returns the path parameters
+func convertPath(path string) (string, []parameter) {
+	var params []parameter
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if name, ok := strings.CutPrefix(seg, ":"); ok {
+			segments[i] = "{" + name + "}"
+			params = append(params, parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
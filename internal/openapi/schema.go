@@ -0,0 +1,152 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Schema is an OpenAPI 3 schema object (the subset this API needs)
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// schemaBuilder derives schemas from Go types, collecting named structs as components
+type schemaBuilder struct {
+	components map[string]*Schema
+	aliases    map[string]string // validate tag aliases, e.g. "verse_limit" -> "min=1,max=50"
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{components: make(map[string]*Schema)}
+}
+
+// ref returns a $ref to the component for v's type, registering it on first use
+func (b *schemaBuilder) ref(v any) *Schema {
+	return b.schema(reflect.TypeOf(v))
+}
+
+// schema returns the schema for t; named structs become component references
+func (b *schemaBuilder) schema(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := b.schema(t.Elem())
+		if s.Ref != "" {
+			return s // Siblings of $ref are ignored in OpenAPI 3.0
+		}
+		s.Nullable = true
+		return s
+	case reflect.Struct:
+		name := t.Name()
+		if name == "" {
+			return b.object(t)
+		}
+		if _, ok := b.components[name]; !ok {
+			b.components[name] = nil // Reserve the name so recursive types terminate
+			b.components[name] = b.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: b.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	default:
+		return &Schema{}
+	}
+}
+
+// object builds an inline object schema from t's JSON-tagged fields
+// Embedded structs are flattened as encoding/json does. A field is required unless
+// it is omitempty, `validate:"min=..,max=.."` tags become numeric bounds and
+// `validate:"oneof=.."` tags become string enums.
+func (b *schemaBuilder) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	b.addFields(s, t)
+	return s
+}
+
+func (b *schemaBuilder) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			b.addFields(s, f.Type)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := b.schema(f.Type)
+		applyValidate(prop, b.expandAliases(f.Tag.Get("validate")))
+		s.Properties[name] = prop
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// expandAliases replaces any registered aliases in a validate tag with their rules
+func (b *schemaBuilder) expandAliases(rules string) string {
+	parts := strings.Split(rules, ",")
+	for i, rule := range parts {
+		if expanded, ok := b.aliases[rule]; ok {
+			parts[i] = expanded
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// applyValidate maps validate min/max rules onto a numeric schema and oneof onto a string enum
+func applyValidate(s *Schema, rules string) {
+	for _, rule := range strings.Split(rules, ",") {
+		key, value, ok := strings.Cut(rule, "=")
+		if !ok {
+			continue
+		}
+		if s.Type == "string" {
+			if key == "oneof" {
+				s.Enum = strings.Fields(value)
+			}
+			continue
+		}
+		if s.Type != "integer" && s.Type != "number" {
+			return
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "min":
+			s.Minimum = &n
+		case "max":
+			s.Maximum = &n
+		}
+	}
+}
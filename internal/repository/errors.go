@@ -0,0 +1,11 @@
+package repository
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when a requested entity does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrNoEmbedding is returned when a verse exists but has no stored embedding
+	ErrNoEmbedding = errors.New("verse has no embedding")
+)
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sola-scriptura-search-api/internal/logging"
+	"github.com/sola-scriptura-search-api/internal/metrics"
+	"github.com/sola-scriptura-search-api/internal/models"
+)
+
+// Ensure FallbackVectorSearchRepository implements VectorSearchRepository
+var _ VectorSearchRepository = (*FallbackVectorSearchRepository)(nil)
+
+// FallbackVectorSearchRepository serves searches from a primary backend, retrying
+// against a secondary one when the primary fails with a retryable error
+// Both must report scores through NormalizeScore so results are comparable.
+type FallbackVectorSearchRepository struct {
+	primary      VectorSearchRepository
+	fallback     VectorSearchRepository
+	retryable    func(error) bool
+	fallbackName string // Backend label for logs and metrics, e.g. "pgvector"
+}
+
+// NewFallbackVectorSearchRepository wraps primary so retryable errors fall back to fallback
+func NewFallbackVectorSearchRepository(primary, fallback VectorSearchRepository, fallbackName string, retryable func(error) bool) *FallbackVectorSearchRepository {
+	return &FallbackVectorSearchRepository{
+		primary:      primary,
+		fallback:     fallback,
+		retryable:    retryable,
+		fallbackName: fallbackName,
+	}
+}
+
+// SearchVersesByEmbedding searches the primary backend, falling back on retryable errors
+// Errors caused by the caller's context (cancellation, request deadline) are returned as is
+func (r *FallbackVectorSearchRepository) SearchVersesByEmbedding(ctx context.Context, embedding []float64, opts models.VectorSearchOptions) ([]models.ScoredVerse, error) {
+	verses, err := r.primary.SearchVersesByEmbedding(ctx, embedding, opts)
+	if err == nil || ctx.Err() != nil || !r.retryable(err) {
+		return verses, err
+	}
+
+	logging.FromContext(ctx).Warn("vector search failed; falling back", "fallback", r.fallbackName, "error", err)
+	verses, fallbackErr := r.fallback.SearchVersesByEmbedding(ctx, embedding, opts)
+	metrics.VectorFallbacks.WithLabelValues(r.fallbackName, metrics.Outcome(fallbackErr)).Inc()
+	if fallbackErr != nil {
+		logging.FromContext(ctx).Error("fallback vector search failed", "fallback", r.fallbackName, "error", fallbackErr)
+		return nil, err
+	}
+	return verses, nil
+}
+
+// Ping checks the primary backend only, so health checks still surface its outages
+func (r *FallbackVectorSearchRepository) Ping(ctx context.Context) error {
+	return r.primary.Ping(ctx)
+}
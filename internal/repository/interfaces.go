@@ -8,14 +8,78 @@ import (
 
 // VectorSearchRepository defines operations for vector similarity search
 type VectorSearchRepository interface {
-	// SearchVersesByEmbedding performs vector similarity search on verses
-	SearchVersesByEmbedding(ctx context.Context, embedding []float64, topK int) ([]models.ScoredVerse, error)
+	// SearchVersesByEmbedding performs vector similarity search on verses matching opts.Filter
+	SearchVersesByEmbedding(ctx context.Context, embedding []float64, opts models.VectorSearchOptions) ([]models.ScoredVerse, error)
+	// Ping verifies the backend is reachable and can serve queries
+	Ping(ctx context.Context) error
 }
 
 // TopicRepository defines operations for topical index data access
 type TopicRepository interface {
-	// SearchByWords searches topics by keyword matching
-	SearchByWords(ctx context.Context, words []string, topK int) ([]models.TopicSearchResult, error)
-	// GetTopicVerses returns verses mapped to a topic
-	GetTopicVerses(ctx context.Context, topicID string, limit int) ([]models.Citation, error)
+	// SearchByWords searches topics by keyword matching, scaling each term's score by its weight
+	SearchByWords(ctx context.Context, terms []models.QueryTerm, topK int) ([]models.TopicSearchResult, error)
+	// GetTopicVerses returns verses mapped to a topic that match filter
+	// Returns ErrNotFound if the topic does not exist; an existing topic with no matching
+	// verses (or none on the requested page) returns an empty slice
+	GetTopicVerses(ctx context.Context, topicID string, opts models.TopicVerseOptions) ([]models.Citation, error)
+	// SearchTopicVerses ranks the topic's mapped verses matching opts by similarity of their
+	// stored embedding to embedding, skipping verses without one
+	// Returns ErrNotFound if the topic does not exist
+	SearchTopicVerses(ctx context.Context, topicID string, embedding []float64, opts models.TopicVerseOptions) ([]models.ScoredVerse, error)
+	// GetChapterRefs returns up to limit "Book Chapter" labels per topic for the chapters
+	// holding most of its verses matching filter, keyed by topic id
+	GetChapterRefs(ctx context.Context, topicIDs []string, limit int, filter models.VerseFilter) (map[string][]string, error)
+	// GetTopVerses returns each topic's highest-importance verse matching filter, keyed by topic id
+	GetTopVerses(ctx context.Context, topicIDs []string, filter models.VerseFilter) (map[string]models.Citation, error)
+	// GetTopicsVerses returns up to opts.Limit verses per topic matching opts, most important
+	// first, keyed by topic id; opts.Offset is ignored and unknown topics are omitted
+	GetTopicsVerses(ctx context.Context, topicIDs []string, opts models.TopicVerseOptions) (map[string][]models.Citation, error)
+	// ListTopics returns a page of topics matching opts and the total number matching
+	ListTopics(ctx context.Context, opts models.TopicListOptions) ([]models.TopicSummary, int, error)
+	// GetTopic returns a topic's metadata and total mapped verse count (without verses)
+	// Returns ErrNotFound if the topic does not exist
+	GetTopic(ctx context.Context, topicID string) (*models.TopicDetail, error)
+	// GetVerseTopics returns up to limit topics a verse is mapped to, most important
+	// (by the verse's tier within each topic) first
+	GetVerseTopics(ctx context.Context, verseID string, limit int) ([]models.VerseTopic, error)
+	// GetRelatedTopics returns a topic's parent, siblings and children
+	// Returns ErrNotFound if the topic does not exist
+	GetRelatedTopics(ctx context.Context, topicID string) (*models.RelatedTopics, error)
+	// GetTopicCoverage returns per-tier mapped and canonical verse counts for a topic
+	// Returns ErrNotFound if the topic does not exist
+	GetTopicCoverage(ctx context.Context, topicID string) (*models.TopicCoverage, error)
+}
+
+// VerseRepository defines operations for direct verse data access
+type VerseRepository interface {
+	// GetPopularity returns the normalized popularity (0-1) of each verse keyed by OSIS id
+	GetPopularity(ctx context.Context, verseIDs []string) (map[string]float64, error)
+	// GetVerseEmbedding returns a verse's text and stored embedding
+	// Returns ErrNotFound for unknown verses and ErrNoEmbedding if it has not been embedded
+	GetVerseEmbedding(ctx context.Context, verseID string) (string, []float64, error)
+	// GetPericopes returns the pericope containing each verse, keyed by OSIS id
+	// Verses in books without pericope data are omitted
+	GetPericopes(ctx context.Context, verseIDs []string) (map[string]*models.Pericope, error)
+	// GetVerseWindows returns each verse with up to window verses either side in the
+	// same chapter, in verse order, keyed by the anchor verse's OSIS id
+	GetVerseWindows(ctx context.Context, verseIDs []string, window int) (map[string][]models.Citation, error)
+	// GetVerses returns the verses matching filter among verseIDs, keyed by OSIS id
+	GetVerses(ctx context.Context, verseIDs []string, filter models.VerseFilter) (map[string]models.Citation, error)
+	// GetCrossReferences returns up to limit cross-reference targets matching filter per
+	// source verse in canonical order, keyed by the source verse's OSIS id
+	GetCrossReferences(ctx context.Context, verseIDs []string, limit int, filter models.VerseFilter) (map[string][]models.Citation, error)
+	// SearchText performs full-text search over verse text, ranked by text relevance
+	SearchText(ctx context.Context, text string, topK int, filter models.VerseFilter) ([]models.ScoredVerse, error)
+	// SearchPhrase returns verses containing phrase, ranked by text relevance then canonically
+	// exact matches the literal text case-insensitively instead of the stemmed phrase
+	SearchPhrase(ctx context.Context, phrase string, exact bool, topK int, filter models.VerseFilter) ([]models.ScoredVerse, error)
+	// GetRange returns up to limit verses of rng matching filter in canonical order
+	GetRange(ctx context.Context, rng models.VerseRange, limit int, filter models.VerseFilter) ([]models.Citation, error)
+	// RandomVerse returns a uniformly random verse matching filter
+	// Returns ErrNotFound if no verse matches
+	RandomVerse(ctx context.Context, filter models.VerseFilter) (*models.Citation, error)
+	// ListBooks returns the books matching filter in canonical order
+	ListBooks(ctx context.Context, filter models.VerseFilter) ([]models.BookInfo, error)
+	// GetAnnotations returns a verse's theological annotations ordered by source then label
+	GetAnnotations(ctx context.Context, verseID string) ([]models.VerseAnnotation, error)
 }
@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/sola-scriptura-search-api/internal/models"
+)
+
+// verseFilterClause renders filter as " AND ..." conditions against the given
+// book OSIS id, testament and book order columns, appending bind args after existing ones.
+// Returns an empty clause when the filter places no restriction.
+func verseFilterClause(filter models.VerseFilter, bookCol, testamentCol, bookOrderCol string, args []interface{}) (string, []interface{}) {
+	var conds []string
+	if len(filter.Books) > 0 {
+		args = append(args, pq.Array(filter.Books))
+		conds = append(conds, fmt.Sprintf("%s = ANY($%d)", bookCol, len(args)))
+	}
+	if len(filter.Testaments) > 0 {
+		args = append(args, pq.Array(filter.Testaments))
+		conds = append(conds, fmt.Sprintf("%s = ANY($%d)", testamentCol, len(args)))
+	}
+	if filter.MinBookOrder > 0 {
+		args = append(args, filter.MinBookOrder)
+		conds = append(conds, fmt.Sprintf("%s >= $%d", bookOrderCol, len(args)))
+	}
+	if filter.MaxBookOrder > 0 {
+		args = append(args, filter.MaxBookOrder)
+		conds = append(conds, fmt.Sprintf("%s <= $%d", bookOrderCol, len(args)))
+	}
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " AND " + strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/sola-scriptura-search-api/internal/models"
+)
+
+func TestVerseFilterClauseTestament(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    models.VerseFilter
+		wantWhere string
+		wantArgs  int
+	}{
+		{"no filter", models.VerseFilter{}, "", 1},
+		{"testament only", models.VerseFilter{Testaments: []string{models.TestamentNew}},
+			" AND b.testament = ANY($2)", 2},
+		{"books and testament", models.VerseFilter{Books: []string{"Ps"}, Testaments: []string{models.TestamentOld}},
+			" AND mv.book = ANY($2) AND b.testament = ANY($3)", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := verseFilterClause(tt.filter, "mv.book", "b.testament", "b.book_order", []interface{}{"vec"})
+			if where != tt.wantWhere {
+				t.Errorf("where = %q, want %q", where, tt.wantWhere)
+			}
+			if len(args) != tt.wantArgs {
+				t.Fatalf("got %d args, want %d", len(args), tt.wantArgs)
+			}
+			if len(tt.filter.Testaments) > 0 {
+				testaments, ok := args[len(args)-1].(*pq.StringArray)
+				if !ok || len(*testaments) != 1 || (*testaments)[0] != tt.filter.Testaments[0] {
+					t.Errorf("testament arg = %#v, want %v", args[len(args)-1], tt.filter.Testaments)
+				}
+			}
+		})
+	}
+}
@@ -2,75 +2,207 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pgvector/pgvector-go"
+	"github.com/sola-scriptura-search-api/internal/metrics"
 	"github.com/sola-scriptura-search-api/internal/models"
 	"github.com/sola-scriptura-search-api/internal/repository"
+	"github.com/sola-scriptura-search-api/pkg/cache"
 )
 
+// topicSearchCacheSize bounds the number of distinct topic searches kept in memory
+const topicSearchCacheSize = 1024
+
+// topicsSummaryView is the precomputed topic summary matview
+const topicsSummaryView = "api_views.mv_topics_summary"
+
+// topicsSummaryLive computes the same columns as mv_topics_summary from the live tables
+// Used when the matview is missing, unpopulated, or locked by a non-concurrent refresh
+const topicsSummaryLive = `(
+		SELECT t.id AS topic_id, t.name, t.source, t.category, t.topic, t.sub_topic,
+		       COUNT(tv.verse_id)::int AS verse_count
+		FROM api.topics t
+		LEFT JOIN api.topic_verses tv ON tv.topic_id = t.id
+		GROUP BY t.id, t.name, t.source, t.category, t.topic, t.sub_topic
+	) AS topics_summary`
+
 // TopicRepository implements repository.TopicRepository for PostgreSQL
 type TopicRepository struct {
-	db *sqlx.DB
+	db          *sqlx.DB
+	searchCache *cache.LRU[string, []models.TopicSearchResult]
+	listCache   *cache.LRU[models.TopicListOptions, topicPage]
+
+	// Source-priority multipliers applied to SearchByWords scores, as parallel arrays
+	// for unnest; sources not listed score unscaled
+	weightedSources   []string
+	sourceMultipliers []float64
+
+	distanceMeasure string // Ranks SearchTopicVerses, as for vector search
+}
+
+// topicPage is a cached ListTopics result
+type topicPage struct {
+	topics []models.TopicSummary
+	total  int
 }
 
 // NewTopicRepository creates a new PostgreSQL topic repository
-func NewTopicRepository(db *sqlx.DB) repository.TopicRepository {
-	return &TopicRepository{db: db}
+// Topic search results are cached for cacheTTL; zero disables the cache. sourceWeights
+// scales each source's search scores (e.g. 0.9 for a less curated index; nil = unscaled).
+// distanceMeasure ranks topic verse search (empty = repository.DistanceCosine)
+func NewTopicRepository(db *sqlx.DB, cacheTTL time.Duration, sourceWeights map[string]float64, distanceMeasure string) repository.TopicRepository {
+	size := topicSearchCacheSize
+	if cacheTTL <= 0 {
+		size = 0
+	}
+	if distanceMeasure == "" {
+		distanceMeasure = repository.DistanceCosine
+	}
+	r := &TopicRepository{
+		db:              db,
+		searchCache:     cache.New[string, []models.TopicSearchResult](size, cacheTTL),
+		listCache:       cache.New[models.TopicListOptions, topicPage](size, cacheTTL),
+		distanceMeasure: distanceMeasure,
+	}
+	for source := range sourceWeights {
+		r.weightedSources = append(r.weightedSources, source)
+	}
+	sort.Strings(r.weightedSources)
+	for _, source := range r.weightedSources {
+		r.sourceMultipliers = append(r.sourceMultipliers, sourceWeights[source])
+	}
+	return r
+}
+
+// isSummaryUnavailable reports whether err means mv_topics_summary cannot be read right now
+// 42P01: relation does not exist, 55000: matview not populated, 55P03: lock not available
+func isSummaryUnavailable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	switch pqErr.Code {
+	case "42P01", "55000", "55P03":
+		return true
+	}
+	return false
+}
+
+// summaryRelation marks where queryTopicsSummary substitutes the topic summary relation
+const summaryRelation = "{topics_summary}"
+
+// queryTopicsSummary runs query against mv_topics_summary, falling back to the live tables
+func (r *TopicRepository) queryTopicsSummary(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	rows, err := r.db.QueryxContext(ctx, strings.Replace(query, summaryRelation, topicsSummaryView, 1), args...)
+	if err == nil || !isSummaryUnavailable(err) {
+		return rows, err
+	}
+	log.Printf("WARN: %s unavailable (%v); falling back to live topic tables - refresh the matview", topicsSummaryView, err)
+	return r.db.QueryxContext(ctx, strings.Replace(query, summaryRelation, topicsSummaryLive, 1), args...)
+}
+
+// topicSearchKey builds the cache key for a weighted term search
+func topicSearchKey(terms []models.QueryTerm, topK int) string {
+	var b strings.Builder
+	for _, term := range terms {
+		fmt.Fprintf(&b, "%s^%g|", strings.ToLower(term.Word), term.Weight)
+	}
+	fmt.Fprintf(&b, "%d", topK)
+	return b.String()
 }
 
 // SearchByWords searches topics by keyword matching using mv_topics_summary
 // Matches on topic and sub_topic columns for better relevance
-func (r *TopicRepository) SearchByWords(ctx context.Context, words []string, topK int) ([]models.TopicSearchResult, error) {
-	if len(words) == 0 {
+// Each term's match score is multiplied by its weight before taking the best
+func (r *TopicRepository) SearchByWords(ctx context.Context, terms []models.QueryTerm, topK int) ([]models.TopicSearchResult, error) {
+	start := time.Now()
+	topics, err := r.searchByWords(ctx, terms, topK)
+	metrics.TopicSearchDuration.WithLabelValues(metrics.Outcome(err)).Observe(metrics.Since(start))
+	return topics, err
+}
+
+func (r *TopicRepository) searchByWords(ctx context.Context, terms []models.QueryTerm, topK int) ([]models.TopicSearchResult, error) {
+	if len(terms) == 0 {
 		return []models.TopicSearchResult{}, nil
 	}
 
+	key := topicSearchKey(terms, topK)
+	if cached, ok := r.searchCache.Get(key); ok {
+		return cached, nil
+	}
+
+	// Parameters: $1..$n are the %word% patterns, $n+1..$2n the weights, $2n+1 the limit,
+	// $2n+2 and $2n+3 the source-priority sources and multipliers
+	n := len(terms)
+
 	// Build scoring CASE for each word
 	// Prioritize: exact topic match > topic prefix > sub_topic match > name contains
 	scoreCases := ""
-	for i := range words {
+	matchedCases := ""
+	for i := range terms {
 		if i > 0 {
 			scoreCases += ",\n\t\t\t   "
+			matchedCases += ", "
 		}
 		paramNum := i + 1
+		weightParam := n + i + 1
+		// Report the bare word when this term matched the row
+		matchedCases += fmt.Sprintf("CASE WHEN topic ILIKE $%d OR sub_topic ILIKE $%d OR name ILIKE $%d THEN TRIM('%%' FROM $%d) END",
+			paramNum, paramNum, paramNum, paramNum)
 		// Strip wildcards for scoring comparison (args have %word%)
-		scoreCases += fmt.Sprintf(`CASE
+		scoreCases += fmt.Sprintf(`(CASE
 			   WHEN LOWER(topic) = LOWER(TRIM('%%' FROM $%d)) THEN 1.0
 			   WHEN LOWER(topic) LIKE LOWER(TRIM('%%' FROM $%d)) || '%%' THEN 0.95
 			   WHEN LOWER(sub_topic) = LOWER(TRIM('%%' FROM $%d)) THEN 0.9
 			   WHEN topic ILIKE $%d OR sub_topic ILIKE $%d THEN 0.85
 			   WHEN name ILIKE $%d THEN 0.7
 			   ELSE 0.0
-		       END`, paramNum, paramNum, paramNum, paramNum, paramNum, paramNum)
+		       END) * $%d::float8`, paramNum, paramNum, paramNum, paramNum, paramNum, paramNum, weightParam)
 	}
 
 	// Use mv_topics_summary which has pre-computed verse_count
-	// Match on topic, sub_topic, or name columns
+	// Match on topic, sub_topic, or name columns. The best term match is scaled by the
+	// source's priority multiplier so a weak match in a preferred source can outrank a
+	// marginal one elsewhere; equal scores then prefer the topic with more verses.
 	query := fmt.Sprintf(`
 		SELECT topic_id::text, name, source, COALESCE(category, '') as category, verse_count,
-		       GREATEST(%s) as score
-		FROM api_views.mv_topics_summary
-		WHERE `, scoreCases)
+		       GREATEST(%s) * COALESCE(
+		           (SELECT sw.multiplier FROM unnest($%d::text[], $%d::float8[]) AS sw(weighted_source, multiplier)
+		            WHERE sw.weighted_source = source), 1.0) as score,
+		       ARRAY_REMOVE(ARRAY[%s]::text[], NULL) as matched_words
+		FROM %s
+		WHERE `, scoreCases, 2*n+2, 2*n+3, matchedCases, summaryRelation)
 
-	args := make([]interface{}, 0, len(words)+1)
-	for i, word := range words {
+	args := make([]interface{}, 0, 2*n+3)
+	for i, term := range terms {
 		if i > 0 {
 			query += " OR "
 		}
 		query += fmt.Sprintf("(topic ILIKE $%d OR sub_topic ILIKE $%d OR name ILIKE $%d)", i+1, i+1, i+1)
-		args = append(args, "%"+word+"%")
+		args = append(args, "%"+term.Word+"%")
 	}
-	args = append(args, topK)
+	for _, term := range terms {
+		args = append(args, term.Weight)
+	}
+	args = append(args, topK, pq.Array(r.weightedSources), pq.Array(r.sourceMultipliers))
 
 	query += fmt.Sprintf(`
 		GROUP BY topic_id, name, source, category, topic, sub_topic, verse_count
 		HAVING verse_count > 0
 		ORDER BY score DESC, verse_count DESC
 		LIMIT $%d
-	`, len(words)+1)
+	`, 2*n+1)
 
-	rows, err := r.db.QueryxContext(ctx, query, args...)
+	rows, err := r.queryTopicsSummary(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("search topics by words: %w", err)
 	}
@@ -79,12 +211,13 @@ func (r *TopicRepository) SearchByWords(ctx context.Context, words []string, top
 	var results []models.TopicSearchResult
 	for rows.Next() {
 		var result struct {
-			TopicID    string  `db:"topic_id"`
-			Name       string  `db:"name"`
-			Source     *string `db:"source"`
-			Category   string  `db:"category"`
-			VerseCount int     `db:"verse_count"`
-			Score      float64 `db:"score"`
+			TopicID      string         `db:"topic_id"`
+			Name         string         `db:"name"`
+			Source       *string        `db:"source"`
+			Category     string         `db:"category"`
+			VerseCount   int            `db:"verse_count"`
+			Score        float64        `db:"score"`
+			MatchedWords pq.StringArray `db:"matched_words"`
 		}
 		if err := rows.StructScan(&result); err != nil {
 			return nil, fmt.Errorf("scan topic result: %w", err)
@@ -100,9 +233,10 @@ func (r *TopicRepository) SearchByWords(ctx context.Context, words []string, top
 				Source:   source,
 				Category: result.Category,
 			},
-			Score:      result.Score,
-			VerseCount: result.VerseCount,
-			Category:   result.Category,
+			Score:        result.Score,
+			VerseCount:   result.VerseCount,
+			Category:     result.Category,
+			MatchedWords: result.MatchedWords,
 		})
 	}
 
@@ -113,28 +247,533 @@ func (r *TopicRepository) SearchByWords(ctx context.Context, words []string, top
 	if results == nil {
 		results = []models.TopicSearchResult{}
 	}
+	r.searchCache.Set(key, results)
 	return results, nil
 }
 
-// GetTopicVerses returns verses mapped to a topic
-func (r *TopicRepository) GetTopicVerses(ctx context.Context, topicID string, limit int) ([]models.Citation, error) {
-	query := `
-		SELECT v.osis_verse_id as verse_id, v.text, b.osis_id as book, v.chapter, v.verse
+// topicSortOrders maps each topic list sort to its ORDER BY clause
+var topicSortOrders = map[string]string{
+	models.TopicSortName:       "name, topic_id",
+	models.TopicSortVerseCount: "verse_count DESC, name, topic_id",
+	models.TopicSortSource:     "source, name, topic_id",
+}
+
+// ListTopics returns a page of topics with verses from mv_topics_summary
+func (r *TopicRepository) ListTopics(ctx context.Context, opts models.TopicListOptions) ([]models.TopicSummary, int, error) {
+	if cached, ok := r.listCache.Get(opts); ok {
+		return cached.topics, cached.total, nil
+	}
+
+	orderBy, ok := topicSortOrders[opts.Sort]
+	if !ok {
+		orderBy = topicSortOrders[models.TopicSortName]
+	}
+
+	where := "verse_count > 0"
+	var args []interface{}
+	if opts.Category != "" {
+		args = append(args, opts.Category)
+		where += fmt.Sprintf(" AND category = $%d", len(args))
+	}
+	if opts.Source != "" {
+		args = append(args, opts.Source)
+		where += fmt.Sprintf(" AND source = $%d", len(args))
+	}
+
+	var total int
+	countRows, err := r.queryTopicsSummary(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s`, summaryRelation, where), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count topics: %w", err)
+	}
+	if countRows.Next() {
+		err = countRows.Scan(&total)
+	}
+	countRows.Close()
+	if err == nil {
+		err = countRows.Err()
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("count topics: %w", err)
+	}
+
+	args = append(args, opts.Limit, opts.Offset)
+	rows, err := r.queryTopicsSummary(ctx, fmt.Sprintf(`
+		SELECT topic_id::text, name, COALESCE(source, '') as source,
+		       COALESCE(category, '') as category, verse_count
+		FROM %s
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, summaryRelation, where, orderBy, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list topics: %w", err)
+	}
+	defer rows.Close()
+
+	topics := []models.TopicSummary{}
+	for rows.Next() {
+		var t models.TopicSummary
+		if err := rows.StructScan(&t); err != nil {
+			return nil, 0, fmt.Errorf("scan topic: %w", err)
+		}
+		topics = append(topics, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate topics: %w", err)
+	}
+
+	r.listCache.Set(opts, topicPage{topics: topics, total: total})
+	return topics, total, nil
+}
+
+// GetTopicVerses returns verses mapped to a topic that match opts, most important first
+func (r *TopicRepository) GetTopicVerses(ctx context.Context, topicID string, opts models.TopicVerseOptions) ([]models.Citation, error) {
+	args := []interface{}{topicID, opts.Limit, opts.Offset}
+	where, args := verseFilterClause(opts.Filter, "b.osis_id", "b.testament", "b.book_order", args)
+	if opts.MaxTier > 0 {
+		args = append(args, opts.MaxTier)
+		where += fmt.Sprintf(" AND tv.importance_tier <= $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT v.osis_verse_id as verse_id, v.text, b.osis_id as book, v.chapter, v.verse,
+		       tv.importance_tier
 		FROM api.topic_verses tv
 		JOIN api.verses v ON tv.verse_id = v.id
 		JOIN api.books b ON v.book_id = b.id
-		WHERE tv.topic_id = $1
+		WHERE tv.topic_id = $1%s
 		ORDER BY tv.importance_tier, b.book_order, v.chapter, v.verse
-		LIMIT $2
-	`
+		LIMIT $2 OFFSET $3
+	`, where)
 
 	var verses []models.Citation
-	if err := r.db.SelectContext(ctx, &verses, query, topicID, limit); err != nil {
+	if err := r.db.SelectContext(ctx, &verses, query, args...); err != nil {
 		return nil, fmt.Errorf("get topic verses: %w", err)
 	}
 
-	if verses == nil {
+	// Only an empty result needs telling apart from an unknown topic
+	if len(verses) == 0 {
+		exists, err := r.topicExists(ctx, topicID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, repository.ErrNotFound
+		}
 		verses = []models.Citation{}
 	}
 	return verses, nil
 }
+
+// SearchTopicVerses ranks a topic's mapped verses by the repository's distance measure to embedding
+// Topics map at most a few hundred verses, so this is an exact scan of api.verses
+// embeddings rather than an index search
+func (r *TopicRepository) SearchTopicVerses(ctx context.Context, topicID string, embedding []float64, opts models.TopicVerseOptions) ([]models.ScoredVerse, error) {
+	exists, err := r.topicExists(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	args := []interface{}{topicID, opts.Limit, pgvector.NewVector(float32Slice(embedding))}
+	where, args := verseFilterClause(opts.Filter, "b.osis_id", "b.testament", "b.book_order", args)
+	if opts.MaxTier > 0 {
+		args = append(args, opts.MaxTier)
+		where += fmt.Sprintf(" AND tv.importance_tier <= $%d", len(args))
+	}
+
+	rows, err := r.db.QueryxContext(ctx, fmt.Sprintf(`
+		SELECT v.osis_verse_id, b.osis_id, v.chapter, v.verse, v.text, b.book_order,
+		       v.embedding %s $3::vector as distance
+		FROM api.topic_verses tv
+		JOIN api.verses v ON tv.verse_id = v.id
+		JOIN api.books b ON v.book_id = b.id
+		WHERE tv.topic_id = $1 AND v.embedding IS NOT NULL%s
+		ORDER BY distance, b.book_order, v.chapter, v.verse
+		LIMIT $2
+	`, distanceOperator(r.distanceMeasure), where), args...)
+	if err != nil {
+		return nil, fmt.Errorf("search topic verses: %w", err)
+	}
+	defer rows.Close()
+
+	verses := []models.ScoredVerse{}
+	for rows.Next() {
+		var v models.ScoredVerse
+		var distance float64
+		if err := rows.Scan(&v.VerseID, &v.Book, &v.Chapter, &v.Verse, &v.Text, &v.BookOrder, &distance); err != nil {
+			return nil, fmt.Errorf("scan topic verse: %w", err)
+		}
+		distance = measureDistance(r.distanceMeasure, distance)
+		v.Score = repository.NormalizeScore(r.distanceMeasure, distance)
+		v.DistanceMeasure, v.Distance = r.distanceMeasure, distance
+		verses = append(verses, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate topic verses: %w", err)
+	}
+	return verses, nil
+}
+
+// GetChapterRefs returns each topic's most represented chapters as "Book Chapter" labels
+// Chapters are ordered by how many of the topic's verses they hold, then canonically
+func (r *TopicRepository) GetChapterRefs(ctx context.Context, topicIDs []string, limit int, filter models.VerseFilter) (map[string][]string, error) {
+	refs := make(map[string][]string, len(topicIDs))
+	if len(topicIDs) == 0 {
+		return refs, nil
+	}
+
+	args := []interface{}{pq.Array(topicIDs), limit}
+	where, args := verseFilterClause(filter, "b.osis_id", "b.testament", "b.book_order", args)
+
+	var rows []struct {
+		TopicID string `db:"topic_id"`
+		Ref     string `db:"ref"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, fmt.Sprintf(`
+		SELECT topic_id, ref
+		FROM (
+			SELECT tv.topic_id::text as topic_id, b.name || ' ' || v.chapter as ref,
+			       ROW_NUMBER() OVER (
+			           PARTITION BY tv.topic_id ORDER BY COUNT(*) DESC, MIN(b.book_order), v.chapter
+			       ) as rn
+			FROM api.topic_verses tv
+			JOIN api.verses v ON tv.verse_id = v.id
+			JOIN api.books b ON v.book_id = b.id
+			WHERE tv.topic_id::text = ANY($1)%s
+			GROUP BY tv.topic_id, b.name, v.chapter
+		) ranked
+		WHERE rn <= $2
+		ORDER BY topic_id, rn
+	`, where), args...); err != nil {
+		return nil, fmt.Errorf("get topic chapter refs: %w", err)
+	}
+
+	for _, row := range rows {
+		refs[row.TopicID] = append(refs[row.TopicID], row.Ref)
+	}
+	return refs, nil
+}
+
+// GetTopVerses returns each topic's highest-importance verse matching filter in one query
+// Topics with no matching verses are omitted from the result
+func (r *TopicRepository) GetTopVerses(ctx context.Context, topicIDs []string, filter models.VerseFilter) (map[string]models.Citation, error) {
+	topVerses := make(map[string]models.Citation, len(topicIDs))
+	if len(topicIDs) == 0 {
+		return topVerses, nil
+	}
+
+	args := []interface{}{pq.Array(topicIDs)}
+	where, args := verseFilterClause(filter, "b.osis_id", "b.testament", "b.book_order", args)
+
+	query := fmt.Sprintf(`
+		SELECT DISTINCT ON (tv.topic_id)
+		       tv.topic_id::text as topic_id, v.osis_verse_id as verse_id, v.text,
+		       b.osis_id as book, v.chapter, v.verse, tv.importance_tier
+		FROM api.topic_verses tv
+		JOIN api.verses v ON tv.verse_id = v.id
+		JOIN api.books b ON v.book_id = b.id
+		WHERE tv.topic_id::text = ANY($1)%s
+		ORDER BY tv.topic_id, tv.importance_tier, b.book_order, v.chapter, v.verse
+	`, where)
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get topic top verses: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row struct {
+			TopicID string `db:"topic_id"`
+			models.Citation
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return nil, fmt.Errorf("scan topic top verse: %w", err)
+		}
+		topVerses[row.TopicID] = row.Citation
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate topic top verses: %w", err)
+	}
+	return topVerses, nil
+}
+
+// GetTopicsVerses returns up to opts.Limit verses per topic matching opts in one query,
+// each topic's ordered as GetTopicVerses orders them
+// Topics with no matching verses are omitted from the result
+func (r *TopicRepository) GetTopicsVerses(ctx context.Context, topicIDs []string, opts models.TopicVerseOptions) (map[string][]models.Citation, error) {
+	verses := make(map[string][]models.Citation, len(topicIDs))
+	if len(topicIDs) == 0 {
+		return verses, nil
+	}
+
+	args := []interface{}{pq.Array(topicIDs), opts.Limit}
+	where, args := verseFilterClause(opts.Filter, "b.osis_id", "b.testament", "b.book_order", args)
+	if opts.MaxTier > 0 {
+		args = append(args, opts.MaxTier)
+		where += fmt.Sprintf(" AND tv.importance_tier <= $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT topic_id, verse_id, text, book, chapter, verse, importance_tier
+		FROM (
+			SELECT tv.topic_id::text as topic_id, v.osis_verse_id as verse_id, v.text,
+			       b.osis_id as book, v.chapter, v.verse, tv.importance_tier,
+			       ROW_NUMBER() OVER (
+			           PARTITION BY tv.topic_id
+			           ORDER BY tv.importance_tier, b.book_order, v.chapter, v.verse
+			       ) as rn
+			FROM api.topic_verses tv
+			JOIN api.verses v ON tv.verse_id = v.id
+			JOIN api.books b ON v.book_id = b.id
+			WHERE tv.topic_id::text = ANY($1)%s
+		) ranked
+		WHERE rn <= $2
+		ORDER BY topic_id, rn
+	`, where)
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get topics verses: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row struct {
+			TopicID string `db:"topic_id"`
+			models.Citation
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return nil, fmt.Errorf("scan topics verse: %w", err)
+		}
+		verses[row.TopicID] = append(verses[row.TopicID], row.Citation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate topics verses: %w", err)
+	}
+	return verses, nil
+}
+
+// GetVerseTopics returns up to limit topics a verse is mapped to, ordered by the verse's
+// importance tier within each topic, then by topic size
+func (r *TopicRepository) GetVerseTopics(ctx context.Context, verseID string, limit int) ([]models.VerseTopic, error) {
+	rows, err := r.queryTopicsSummary(ctx, fmt.Sprintf(`
+		SELECT topic_id::text, name, COALESCE(source, '') as source,
+		       COALESCE(category, '') as category, verse_count,
+		       COALESCE(tv.importance_tier, 3) as importance_tier
+		FROM api.topic_verses tv
+		JOIN api.verses v ON tv.verse_id = v.id
+		JOIN %s USING (topic_id)
+		WHERE v.osis_verse_id = $1
+		ORDER BY importance_tier, verse_count DESC, name
+		LIMIT $2
+	`, summaryRelation), verseID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get verse topics: %w", err)
+	}
+	defer rows.Close()
+
+	topics := []models.VerseTopic{}
+	for rows.Next() {
+		var t models.VerseTopic
+		if err := rows.StructScan(&t); err != nil {
+			return nil, fmt.Errorf("scan verse topic: %w", err)
+		}
+		topics = append(topics, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate verse topics: %w", err)
+	}
+	return topics, nil
+}
+
+// GetTopic returns a topic's metadata and mapped verse count
+func (r *TopicRepository) GetTopic(ctx context.Context, topicID string) (*models.TopicDetail, error) {
+	var topic models.TopicDetail
+	err := r.db.GetContext(ctx, &topic, `
+		SELECT t.id::text as topic_id, t.name, COALESCE(t.slug, '') as slug,
+		       COALESCE(t.source, '') as source, COALESCE(t.category, '') as category,
+		       COALESCE(t.description, '') as description,
+		       (SELECT COUNT(*) FROM api.topic_verses tv WHERE tv.topic_id = t.id) as verse_count
+		FROM api.topics t
+		WHERE t.id = $1
+	`, topicID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get topic: %w", err)
+	}
+	return &topic, nil
+}
+
+// GetRelatedTopics returns a topic's parent, siblings and children in the hierarchy
+// A topic's sub_topic names its parent; names match case-insensitively within the source
+func (r *TopicRepository) GetRelatedTopics(ctx context.Context, topicID string) (*models.RelatedTopics, error) {
+	var topic struct {
+		Name   string `db:"name"`
+		Source string `db:"source"`
+		Parent string `db:"parent"`
+	}
+	err := r.db.GetContext(ctx, &topic, `
+		SELECT name, COALESCE(source, '') as source, COALESCE(TRIM(sub_topic), '') as parent
+		FROM api.topics
+		WHERE id = $1
+	`, topicID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get topic: %w", err)
+	}
+
+	rows, err := r.queryTopicsSummary(ctx, fmt.Sprintf(`
+		SELECT topic_id::text, name, COALESCE(source, '') as source,
+		       COALESCE(category, '') as category, verse_count,
+		       CASE WHEN LOWER(TRIM(sub_topic)) = LOWER($2) THEN '%s'
+		            WHEN LOWER(name) = LOWER($3) THEN '%s'
+		            ELSE '%s' END as relation
+		FROM %s
+		WHERE COALESCE(source, '') = $4 AND topic_id::text <> $1
+		  AND (LOWER(TRIM(sub_topic)) = LOWER($2)
+		       OR ($3 <> '' AND (LOWER(name) = LOWER($3) OR LOWER(TRIM(sub_topic)) = LOWER($3))))
+		ORDER BY name
+	`, models.TopicRelationChild, models.TopicRelationParent, models.TopicRelationSibling, summaryRelation),
+		topicID, topic.Name, topic.Parent, topic.Source)
+	if err != nil {
+		return nil, fmt.Errorf("get related topics: %w", err)
+	}
+	defer rows.Close()
+
+	related := &models.RelatedTopics{
+		TopicID:  topicID,
+		Siblings: []models.TopicSummary{},
+		Children: []models.TopicSummary{},
+	}
+	for rows.Next() {
+		var t models.RelatedTopic
+		if err := rows.StructScan(&t); err != nil {
+			return nil, fmt.Errorf("scan related topic: %w", err)
+		}
+		switch t.Relation {
+		case models.TopicRelationParent:
+			if related.Parent == nil {
+				related.Parent = &t.TopicSummary
+			}
+		case models.TopicRelationChild:
+			related.Children = append(related.Children, t.TopicSummary)
+		default:
+			related.Siblings = append(related.Siblings, t.TopicSummary)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate related topics: %w", err)
+	}
+	return related, nil
+}
+
+// topicExists reports whether api.topics has a topic with topicID
+func (r *TopicRepository) topicExists(ctx context.Context, topicID string) (bool, error) {
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM api.topics WHERE id = $1)`, topicID); err != nil {
+		return false, fmt.Errorf("check topic exists: %w", err)
+	}
+	return exists, nil
+}
+
+// importanceTiers are the tiers reported by coverage (1=essential, 2=important, 3=supporting)
+var importanceTiers = []int{1, 2, 3}
+
+// GetTopicCoverage returns per-tier mapped and canonical verse counts for a topic
+func (r *TopicRepository) GetTopicCoverage(ctx context.Context, topicID string) (*models.TopicCoverage, error) {
+	exists, err := r.topicExists(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	// Mapped verses per tier (unset tiers count as supporting)
+	var mapped []struct {
+		Tier  int `db:"tier"`
+		Count int `db:"count"`
+	}
+	if err := r.db.SelectContext(ctx, &mapped, `
+		SELECT COALESCE(importance_tier, 3) as tier, COUNT(*) as count
+		FROM api.topic_verses
+		WHERE topic_id = $1
+		GROUP BY 1
+	`, topicID); err != nil {
+		return nil, fmt.Errorf("count mapped verses: %w", err)
+	}
+
+	// Canonical verses per tier, and how many of them are actually mapped
+	var canonical []struct {
+		Tier     int `db:"tier"`
+		Expected int `db:"expected"`
+		Present  int `db:"present"`
+	}
+	if err := r.db.SelectContext(ctx, &canonical, `
+		SELECT c.importance_tier as tier, COUNT(*) as expected, COUNT(tv.verse_id) as present
+		FROM api.topic_canonical_verses c
+		LEFT JOIN api.verses v ON v.osis_verse_id = c.osis_verse_id
+		LEFT JOIN api.topic_verses tv ON tv.topic_id = c.topic_id AND tv.verse_id = v.id
+		WHERE c.topic_id = $1
+		GROUP BY c.importance_tier
+	`, topicID); err != nil {
+		return nil, fmt.Errorf("count canonical verses: %w", err)
+	}
+
+	// Essential canonical verses that are not mapped (or not in the corpus at all)
+	var missing []string
+	if err := r.db.SelectContext(ctx, &missing, `
+		SELECT c.osis_verse_id
+		FROM api.topic_canonical_verses c
+		LEFT JOIN api.verses v ON v.osis_verse_id = c.osis_verse_id
+		LEFT JOIN api.topic_verses tv ON tv.topic_id = c.topic_id AND tv.verse_id = v.id
+		WHERE c.topic_id = $1 AND c.importance_tier = 1 AND tv.verse_id IS NULL
+		ORDER BY c.osis_verse_id
+	`, topicID); err != nil {
+		return nil, fmt.Errorf("find missing essential verses: %w", err)
+	}
+
+	coverage := &models.TopicCoverage{
+		TopicID:          topicID,
+		HasCanonical:     len(canonical) > 0,
+		Tiers:            make([]models.TierCoverage, len(importanceTiers)),
+		MissingEssential: missing,
+	}
+	if coverage.MissingEssential == nil {
+		coverage.MissingEssential = []string{}
+	}
+
+	byTier := make(map[int]*models.TierCoverage, len(importanceTiers))
+	for i, tier := range importanceTiers {
+		coverage.Tiers[i] = models.TierCoverage{Tier: tier}
+		byTier[tier] = &coverage.Tiers[i]
+	}
+	for _, m := range mapped {
+		if tc, ok := byTier[m.Tier]; ok {
+			tc.Mapped = m.Count
+		}
+	}
+	if coverage.HasCanonical {
+		// Report zero (rather than omitting) for tiers the definition leaves empty
+		for i := range coverage.Tiers {
+			coverage.Tiers[i].Expected, coverage.Tiers[i].Present = new(int), new(int)
+		}
+		for _, c := range canonical {
+			if tc, ok := byTier[c.Tier]; ok {
+				*tc.Expected, *tc.Present = c.Expected, c.Present
+			}
+		}
+	}
+
+	return coverage, nil
+}
@@ -2,35 +2,103 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pgvector/pgvector-go"
+	"github.com/sola-scriptura-search-api/internal/metrics"
 	"github.com/sola-scriptura-search-api/internal/models"
 	"github.com/sola-scriptura-search-api/internal/repository"
 )
 
 // VectorSearchRepository implements repository.VectorSearchRepository for PostgreSQL with pgvector
 type VectorSearchRepository struct {
-	db *sqlx.DB
+	db              *sqlx.DB
+	distanceMeasure string
+	embeddingCol    string // Vector expression searched in mv_verses_search
 }
 
-// NewVectorSearchRepository creates a new PostgreSQL vector search repository
-func NewVectorSearchRepository(db *sqlx.DB) repository.VectorSearchRepository {
-	return &VectorSearchRepository{db: db}
+// NewVectorSearchRepository creates a new PostgreSQL vector search repository ranking by
+// distanceMeasure (empty = repository.DistanceCosine)
+// Measures other than cosine only use an index built with the matching operator class.
+// With useAugmented, verses are searched by their enriched embedding where one has been
+// stored (as upserted to Vertex AI by enrichment apply) and their base embedding
+// otherwise; that expression is not indexed, so searches are exact scans.
+func NewVectorSearchRepository(db *sqlx.DB, distanceMeasure string, useAugmented bool) repository.VectorSearchRepository {
+	if distanceMeasure == "" {
+		distanceMeasure = repository.DistanceCosine
+	}
+	embeddingCol := "mv.embedding"
+	if useAugmented {
+		embeddingCol = "COALESCE(mv.augmented_embedding, mv.embedding)"
+	}
+	return &VectorSearchRepository{db: db, distanceMeasure: distanceMeasure, embeddingCol: embeddingCol}
 }
 
 // SearchVersesByEmbedding performs vector similarity search on verses using pgvector
-func (r *VectorSearchRepository) SearchVersesByEmbedding(ctx context.Context, embedding []float64, topK int) ([]models.ScoredVerse, error) {
+func (r *VectorSearchRepository) SearchVersesByEmbedding(ctx context.Context, embedding []float64, opts models.VectorSearchOptions) ([]models.ScoredVerse, error) {
+	start := time.Now()
+	verses, err := r.searchVersesByEmbedding(ctx, embedding, opts)
+	metrics.VectorSearchDuration.WithLabelValues("pgvector", metrics.Outcome(err)).Observe(metrics.Since(start))
+	return verses, err
+}
+
+// Ping verifies the verse search view is queryable
+func (r *VectorSearchRepository) Ping(ctx context.Context) error {
+	var one int
+	err := r.db.QueryRowContext(ctx, `SELECT 1 FROM api_views.mv_verses_search LIMIT 1`).Scan(&one)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("query verse search view: %w", err)
+	}
+	return nil
+}
+
+func (r *VectorSearchRepository) searchVersesByEmbedding(ctx context.Context, embedding []float64, opts models.VectorSearchOptions) ([]models.ScoredVerse, error) {
 	vec := pgvector.NewVector(float32Slice(embedding))
+	op := distanceOperator(r.distanceMeasure)
 
-	rows, err := r.db.QueryxContext(ctx, `
-		SELECT verse_id, book, chapter, verse, text,
-		       1 - (embedding <=> $1::vector) as score
-		FROM api_views.mv_verses_search
-		ORDER BY embedding <=> $1::vector
-		LIMIT $2
-	`, vec, topK)
+	args := []interface{}{vec, opts.TopK, opts.Offset}
+	where, args := verseFilterClause(opts.Filter, "mv.book", "b.testament", "b.book_order", args)
+
+	// Only ship the stored vectors back when the caller needs them
+	returnedCol := "NULL::vector"
+	if opts.IncludeEmbeddings {
+		returnedCol = r.embeddingCol
+	}
+
+	query := fmt.Sprintf(`
+		SELECT mv.verse_id, mv.book, mv.chapter, mv.verse, mv.text, b.book_order,
+		       %[4]s %[1]s $1::vector as distance, %[2]s as embedding
+		FROM api_views.mv_verses_search mv
+		JOIN api.books b ON b.osis_id = mv.book
+		WHERE TRUE%[3]s
+		ORDER BY %[4]s %[1]s $1::vector
+		LIMIT $2 OFFSET $3
+	`, op, returnedCol, where, r.embeddingCol)
+
+	// Match Vertex crowding: rank within each book and keep the closest MaxPerBook
+	if opts.MaxPerBook > 0 {
+		args = append(args, opts.MaxPerBook)
+		query = fmt.Sprintf(`
+			SELECT verse_id, book, chapter, verse, text, book_order, distance, embedding
+			FROM (
+				SELECT mv.verse_id, mv.book, mv.chapter, mv.verse, mv.text, b.book_order,
+				       %[5]s %[1]s $1::vector as distance, %[2]s as embedding,
+				       ROW_NUMBER() OVER (PARTITION BY mv.book ORDER BY %[5]s %[1]s $1::vector) as book_rank
+				FROM api_views.mv_verses_search mv
+				JOIN api.books b ON b.osis_id = mv.book
+				WHERE TRUE%[3]s
+			) ranked
+			WHERE book_rank <= $%[4]d
+			ORDER BY distance
+			LIMIT $2 OFFSET $3
+		`, op, returnedCol, where, len(args), r.embeddingCol)
+	}
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("vector search verses: %w", err)
 	}
@@ -39,9 +107,17 @@ func (r *VectorSearchRepository) SearchVersesByEmbedding(ctx context.Context, em
 	var results []models.ScoredVerse
 	for rows.Next() {
 		var v models.ScoredVerse
-		if err := rows.Scan(&v.VerseID, &v.Book, &v.Chapter, &v.Verse, &v.Text, &v.Score); err != nil {
+		var vec pgvector.Vector
+		var distance float64
+		if err := rows.Scan(&v.VerseID, &v.Book, &v.Chapter, &v.Verse, &v.Text, &v.BookOrder, &distance, &nullVector{&vec}); err != nil {
 			return nil, fmt.Errorf("scan verse result: %w", err)
 		}
+		distance = measureDistance(r.distanceMeasure, distance)
+		v.Score = repository.NormalizeScore(r.distanceMeasure, distance)
+		v.DistanceMeasure, v.Distance = r.distanceMeasure, distance
+		if opts.IncludeEmbeddings {
+			v.Embedding = float64Slice(vec.Slice())
+		}
 		results = append(results, v)
 	}
 
@@ -55,6 +131,31 @@ func (r *VectorSearchRepository) SearchVersesByEmbedding(ctx context.Context, em
 	return results, nil
 }
 
+// distanceOperator returns the pgvector operator ordering vectors by measure, closest first
+func distanceOperator(measure string) string {
+	switch measure {
+	case repository.DistanceDotProduct:
+		return "<#>" // Negative inner product
+	case repository.DistanceSquaredL2:
+		return "<->" // Euclidean distance
+	default:
+		return "<=>" // Cosine distance
+	}
+}
+
+// measureDistance converts a distanceOperator result into measure's distance as Vertex
+// AI reports it, so NormalizeScore treats both backends alike
+func measureDistance(measure string, value float64) float64 {
+	switch measure {
+	case repository.DistanceDotProduct:
+		return -value
+	case repository.DistanceSquaredL2:
+		return value * value
+	default:
+		return value
+	}
+}
+
 // float32Slice converts []float64 to []float32 for pgvector
 func float32Slice(f64 []float64) []float32 {
 	f32 := make([]float32, len(f64))
@@ -63,3 +164,25 @@ func float32Slice(f64 []float64) []float32 {
 	}
 	return f32
 }
+
+// float64Slice converts a pgvector []float32 back to []float64
+func float64Slice(f32 []float32) []float64 {
+	f64 := make([]float64, len(f32))
+	for i, v := range f32 {
+		f64[i] = float64(v)
+	}
+	return f64
+}
+
+// nullVector scans a possibly-NULL vector column, leaving the target empty for NULL
+type nullVector struct {
+	v *pgvector.Vector
+}
+
+// Scan implements sql.Scanner
+func (n *nullVector) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	return n.v.Scan(src)
+}
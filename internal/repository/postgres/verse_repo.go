@@ -0,0 +1,387 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pgvector/pgvector-go"
+	"github.com/sola-scriptura-search-api/internal/models"
+	"github.com/sola-scriptura-search-api/internal/repository"
+)
+
+// VerseRepository implements repository.VerseRepository for PostgreSQL
+type VerseRepository struct {
+	db *sqlx.DB
+}
+
+// NewVerseRepository creates a new PostgreSQL verse repository
+func NewVerseRepository(db *sqlx.DB) repository.VerseRepository {
+	return &VerseRepository{db: db}
+}
+
+// GetPopularity returns the normalized popularity of each verse keyed by OSIS id
+// Verses without a popularity signal are omitted
+func (r *VerseRepository) GetPopularity(ctx context.Context, verseIDs []string) (map[string]float64, error) {
+	popularity := make(map[string]float64, len(verseIDs))
+	if len(verseIDs) == 0 {
+		return popularity, nil
+	}
+
+	rows, err := r.db.QueryxContext(ctx, `
+		SELECT osis_verse_id, popularity
+		FROM api.verses
+		WHERE osis_verse_id = ANY($1) AND popularity > 0
+	`, pq.Array(verseIDs))
+	if err != nil {
+		return nil, fmt.Errorf("get verse popularity: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var verseID string
+		var p float64
+		if err := rows.Scan(&verseID, &p); err != nil {
+			return nil, fmt.Errorf("scan verse popularity: %w", err)
+		}
+		popularity[verseID] = p
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate verse popularity: %w", err)
+	}
+	return popularity, nil
+}
+
+// GetVerseEmbedding returns a verse's text and stored embedding
+func (r *VerseRepository) GetVerseEmbedding(ctx context.Context, verseID string) (string, []float64, error) {
+	var row struct {
+		Text      string         `db:"text"`
+		Embedding sql.NullString `db:"embedding"`
+	}
+	err := r.db.GetContext(ctx, &row, `
+		SELECT text, embedding::text as embedding
+		FROM api.verses
+		WHERE osis_verse_id = $1
+	`, verseID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("get verse embedding: %w", err)
+	}
+	if !row.Embedding.Valid {
+		return row.Text, nil, repository.ErrNoEmbedding
+	}
+
+	var vec pgvector.Vector
+	if err := vec.Parse(row.Embedding.String); err != nil {
+		return "", nil, fmt.Errorf("parse verse embedding: %w", err)
+	}
+	return row.Text, float64Slice(vec.Slice()), nil
+}
+
+// anchoredCitation is a verse row tagged with the result verse it was fetched for
+type anchoredCitation struct {
+	Anchor string `db:"anchor"`
+	Title  string `db:"title"`
+	models.Citation
+}
+
+// GetPericopes returns the pericope containing each verse with all of its verses
+// When pericopes overlap, the shortest one containing the verse is used
+func (r *VerseRepository) GetPericopes(ctx context.Context, verseIDs []string) (map[string]*models.Pericope, error) {
+	pericopes := make(map[string]*models.Pericope, len(verseIDs))
+	if len(verseIDs) == 0 {
+		return pericopes, nil
+	}
+
+	var rows []anchoredCitation
+	if err := r.db.SelectContext(ctx, &rows, `
+		WITH hits AS (
+			SELECT DISTINCT ON (h.osis_verse_id)
+			       h.osis_verse_id as anchor, COALESCE(p.title, '') as title, p.book_id,
+			       p.start_chapter, p.start_verse, p.end_chapter, p.end_verse
+			FROM api.verses h
+			JOIN api.pericopes p ON p.book_id = h.book_id
+			 AND (h.chapter, h.verse) >= (p.start_chapter, p.start_verse)
+			 AND (h.chapter, h.verse) <= (p.end_chapter, p.end_verse)
+			WHERE h.osis_verse_id = ANY($1)
+			ORDER BY h.osis_verse_id, p.end_chapter - p.start_chapter, p.end_verse - p.start_verse
+		)
+		SELECT hits.anchor, hits.title,
+		       v.osis_verse_id as verse_id, v.text, b.osis_id as book, v.chapter, v.verse
+		FROM hits
+		JOIN api.verses v ON v.book_id = hits.book_id
+		 AND (v.chapter, v.verse) >= (hits.start_chapter, hits.start_verse)
+		 AND (v.chapter, v.verse) <= (hits.end_chapter, hits.end_verse)
+		JOIN api.books b ON v.book_id = b.id
+		ORDER BY hits.anchor, v.chapter, v.verse
+	`, pq.Array(verseIDs)); err != nil {
+		return nil, fmt.Errorf("get pericopes: %w", err)
+	}
+
+	for _, row := range rows {
+		p, ok := pericopes[row.Anchor]
+		if !ok {
+			p = &models.Pericope{Title: row.Title, StartVerseID: row.VerseID}
+			pericopes[row.Anchor] = p
+		}
+		p.Verses = append(p.Verses, row.Citation)
+		p.EndVerseID = row.VerseID
+	}
+	return pericopes, nil
+}
+
+// GetVerseWindows returns each verse with up to window neighbors either side in its chapter
+func (r *VerseRepository) GetVerseWindows(ctx context.Context, verseIDs []string, window int) (map[string][]models.Citation, error) {
+	windows := make(map[string][]models.Citation, len(verseIDs))
+	if len(verseIDs) == 0 {
+		return windows, nil
+	}
+
+	var rows []anchoredCitation
+	if err := r.db.SelectContext(ctx, &rows, `
+		SELECT h.osis_verse_id as anchor, '' as title,
+		       v.osis_verse_id as verse_id, v.text, b.osis_id as book, v.chapter, v.verse
+		FROM api.verses h
+		JOIN api.verses v ON v.book_id = h.book_id AND v.chapter = h.chapter
+		 AND v.verse BETWEEN h.verse - $2 AND h.verse + $2
+		JOIN api.books b ON v.book_id = b.id
+		WHERE h.osis_verse_id = ANY($1)
+		ORDER BY h.osis_verse_id, v.verse
+	`, pq.Array(verseIDs), window); err != nil {
+		return nil, fmt.Errorf("get verse windows: %w", err)
+	}
+
+	for _, row := range rows {
+		windows[row.Anchor] = append(windows[row.Anchor], row.Citation)
+	}
+	return windows, nil
+}
+
+// GetVerses returns the verses matching filter among verseIDs, keyed by OSIS id
+// Unknown ids are omitted
+func (r *VerseRepository) GetVerses(ctx context.Context, verseIDs []string, filter models.VerseFilter) (map[string]models.Citation, error) {
+	verses := make(map[string]models.Citation, len(verseIDs))
+	if len(verseIDs) == 0 {
+		return verses, nil
+	}
+
+	args := []interface{}{pq.Array(verseIDs)}
+	where, args := verseFilterClause(filter, "b.osis_id", "b.testament", "b.book_order", args)
+
+	var rows []models.Citation
+	if err := r.db.SelectContext(ctx, &rows, fmt.Sprintf(`
+		SELECT v.osis_verse_id as verse_id, v.text, b.osis_id as book, v.chapter, v.verse
+		FROM api.verses v
+		JOIN api.books b ON v.book_id = b.id
+		WHERE v.osis_verse_id = ANY($1)%s
+	`, where), args...); err != nil {
+		return nil, fmt.Errorf("get verses: %w", err)
+	}
+
+	for _, row := range rows {
+		verses[row.VerseID] = row
+	}
+	return verses, nil
+}
+
+// GetRange returns up to limit verses of rng matching filter in canonical order
+func (r *VerseRepository) GetRange(ctx context.Context, rng models.VerseRange, limit int, filter models.VerseFilter) ([]models.Citation, error) {
+	endVerse := rng.EndVerse
+	if endVerse == 0 {
+		endVerse = math.MaxInt32
+	}
+
+	args := []interface{}{rng.Book, rng.StartChapter, rng.StartVerse, rng.EndChapter, endVerse, limit}
+	where, args := verseFilterClause(filter, "b.osis_id", "b.testament", "b.book_order", args)
+
+	verses := []models.Citation{}
+	if err := r.db.SelectContext(ctx, &verses, fmt.Sprintf(`
+		SELECT v.osis_verse_id as verse_id, v.text, b.osis_id as book, v.chapter, v.verse
+		FROM api.verses v
+		JOIN api.books b ON v.book_id = b.id
+		WHERE b.osis_id = $1
+		  AND (v.chapter, v.verse) >= ($2, $3)
+		  AND (v.chapter, v.verse) <= ($4, $5)%s
+		ORDER BY b.book_order, v.chapter, v.verse
+		LIMIT $6
+	`, where), args...); err != nil {
+		return nil, fmt.Errorf("get verse range: %w", err)
+	}
+	return verses, nil
+}
+
+// SearchText performs English full-text search over verse text, ranked by ts_rank
+func (r *VerseRepository) SearchText(ctx context.Context, text string, topK int, filter models.VerseFilter) ([]models.ScoredVerse, error) {
+	args := []interface{}{text, topK}
+	where, args := verseFilterClause(filter, "b.osis_id", "b.testament", "b.book_order", args)
+
+	query := fmt.Sprintf(`
+		SELECT v.osis_verse_id, b.osis_id, v.chapter, v.verse, v.text, b.book_order,
+		       ts_rank(to_tsvector('english', v.text), q.query) as score
+		FROM api.verses v
+		JOIN api.books b ON v.book_id = b.id
+		CROSS JOIN websearch_to_tsquery('english', $1) AS q(query)
+		WHERE to_tsvector('english', v.text) @@ q.query%s
+		ORDER BY score DESC
+		LIMIT $2
+	`, where)
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search verse text: %w", err)
+	}
+	defer rows.Close()
+	return scanTextResults(rows)
+}
+
+// SearchPhrase returns verses containing phrase, ranked by ts_rank then canonically
+// The default match is phraseto_tsquery (stemmed words, in order, adjacent); exact
+// matches the literal text with ILIKE. Both ignore the full-text index, which is fine
+// at a single Bible's size.
+func (r *VerseRepository) SearchPhrase(ctx context.Context, phrase string, exact bool, topK int, filter models.VerseFilter) ([]models.ScoredVerse, error) {
+	args := []interface{}{phrase, topK}
+	match := "to_tsvector('english', v.text) @@ q.query"
+	if exact {
+		args = append(args, "%"+likeEscaper.Replace(phrase)+"%")
+		match = fmt.Sprintf("v.text ILIKE $%d", len(args))
+	}
+	where, args := verseFilterClause(filter, "b.osis_id", "b.testament", "b.book_order", args)
+
+	query := fmt.Sprintf(`
+		SELECT v.osis_verse_id, b.osis_id, v.chapter, v.verse, v.text, b.book_order,
+		       ts_rank(to_tsvector('english', v.text), q.query) as score
+		FROM api.verses v
+		JOIN api.books b ON v.book_id = b.id
+		CROSS JOIN phraseto_tsquery('english', $1) AS q(query)
+		WHERE %s%s
+		ORDER BY score DESC, b.book_order, v.chapter, v.verse
+		LIMIT $2
+	`, match, where)
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search verse phrase: %w", err)
+	}
+	defer rows.Close()
+	return scanTextResults(rows)
+}
+
+// likeEscaper escapes LIKE wildcards so a phrase matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// scanTextResults reads SearchText/SearchPhrase rows, never returning a nil slice
+func scanTextResults(rows *sqlx.Rows) ([]models.ScoredVerse, error) {
+	results := []models.ScoredVerse{}
+	for rows.Next() {
+		var v models.ScoredVerse
+		if err := rows.Scan(&v.VerseID, &v.Book, &v.Chapter, &v.Verse, &v.Text, &v.BookOrder, &v.Score); err != nil {
+			return nil, fmt.Errorf("scan text search result: %w", err)
+		}
+		results = append(results, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate text search results: %w", err)
+	}
+	return results, nil
+}
+
+// GetCrossReferences returns up to limit cross-reference targets matching filter for each
+// source verse in canonical order, keyed by the source verse's OSIS id
+func (r *VerseRepository) GetCrossReferences(ctx context.Context, verseIDs []string, limit int, filter models.VerseFilter) (map[string][]models.Citation, error) {
+	refs := make(map[string][]models.Citation, len(verseIDs))
+	if len(verseIDs) == 0 {
+		return refs, nil
+	}
+
+	args := []interface{}{pq.Array(verseIDs), limit}
+	where, args := verseFilterClause(filter, "b.osis_id", "b.testament", "b.book_order", args)
+
+	var rows []anchoredCitation
+	if err := r.db.SelectContext(ctx, &rows, fmt.Sprintf(`
+		SELECT anchor, '' as title, verse_id, text, book, chapter, verse
+		FROM (
+			SELECT src.osis_verse_id as anchor,
+			       v.osis_verse_id as verse_id, v.text, b.osis_id as book, v.chapter, v.verse,
+			       b.book_order,
+			       ROW_NUMBER() OVER (
+			           PARTITION BY src.osis_verse_id ORDER BY b.book_order, v.chapter, v.verse
+			       ) as rn
+			FROM api.refs r
+			JOIN api.verses src ON r.source_verse_id = src.id
+			JOIN api.verses v ON r.target_verse_id = v.id
+			JOIN api.books b ON v.book_id = b.id
+			WHERE src.osis_verse_id = ANY($1)%s
+		) ranked
+		WHERE rn <= $2
+		ORDER BY anchor, book_order, chapter, verse
+	`, where), args...); err != nil {
+		return nil, fmt.Errorf("get cross references: %w", err)
+	}
+
+	for _, row := range rows {
+		refs[row.Anchor] = append(refs[row.Anchor], row.Citation)
+	}
+	return refs, nil
+}
+
+// RandomVerse returns a uniformly random verse matching filter
+func (r *VerseRepository) RandomVerse(ctx context.Context, filter models.VerseFilter) (*models.Citation, error) {
+	where, args := verseFilterClause(filter, "b.osis_id", "b.testament", "b.book_order", nil)
+
+	var verse models.Citation
+	err := r.db.GetContext(ctx, &verse, fmt.Sprintf(`
+		SELECT v.osis_verse_id as verse_id, v.text, b.osis_id as book, v.chapter, v.verse
+		FROM api.verses v
+		JOIN api.books b ON v.book_id = b.id
+		WHERE TRUE%s
+		ORDER BY RANDOM()
+		LIMIT 1
+	`, where), args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get random verse: %w", err)
+	}
+	return &verse, nil
+}
+
+// ListBooks returns the books matching filter in canonical order
+func (r *VerseRepository) ListBooks(ctx context.Context, filter models.VerseFilter) ([]models.BookInfo, error) {
+	where, args := verseFilterClause(filter, "b.osis_id", "b.testament", "b.book_order", nil)
+
+	books := []models.BookInfo{}
+	if err := r.db.SelectContext(ctx, &books, fmt.Sprintf(`
+		SELECT b.osis_id, b.testament, b.book_order, COALESCE(b.genre, '') as genre
+		FROM api.books b
+		WHERE TRUE%s
+		ORDER BY b.book_order
+	`, where), args...); err != nil {
+		return nil, fmt.Errorf("list books: %w", err)
+	}
+	return books, nil
+}
+
+// GetAnnotations returns a verse's theological annotations ordered by source then label
+func (r *VerseRepository) GetAnnotations(ctx context.Context, verseID string) ([]models.VerseAnnotation, error) {
+	annotations := []models.VerseAnnotation{}
+	if err := r.db.SelectContext(ctx, &annotations, `
+		SELECT a.annotation, a.source
+		FROM api.verse_annotations a
+		JOIN api.verses v ON a.verse_id = v.id
+		WHERE v.osis_verse_id = $1
+		ORDER BY a.source, a.annotation
+	`, verseID); err != nil {
+		return nil, fmt.Errorf("get verse annotations: %w", err)
+	}
+	return annotations, nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+)
+
+// Distance measures a vector backend can report neighbors in
+const (
+	DistanceCosine     = "COSINE_DISTANCE"      // 1 - cos(a, b), in [0, 2]
+	DistanceDotProduct = "DOT_PRODUCT_DISTANCE" // a · b; equals cosine similarity for unit vectors
+	DistanceSquaredL2  = "SQUARED_L2_DISTANCE"  // |a - b|²; equals 2 - 2cos(a, b) for unit vectors
+)
+
+// ValidateDistanceMeasure returns an error for an unsupported distance measure
+func ValidateDistanceMeasure(measure string) error {
+	switch measure {
+	case DistanceCosine, DistanceDotProduct, DistanceSquaredL2:
+		return nil
+	}
+	return fmt.Errorf("unsupported distance measure %q", measure)
+}
+
+// NormalizeScore converts a backend distance into a similarity in [0, 1]
+// Every backend reports scores through this so thresholds such as min_score and the
+// topic card minimum mean the same thing whichever produced them. Opposed vectors
+// (negative similarity) clamp to 0.
+func NormalizeScore(measure string, distance float64) float64 {
+	var similarity float64
+	switch measure {
+	case DistanceDotProduct:
+		similarity = distance
+	case DistanceSquaredL2:
+		similarity = 1 - distance/2
+	default:
+		similarity = 1 - distance
+	}
+	return math.Max(0, math.Min(1, similarity))
+}
@@ -2,14 +2,23 @@ package vertex
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"sync"
+	"time"
 
 	aiplatform "cloud.google.com/go/aiplatform/apiv1"
 	aiplatformpb "cloud.google.com/go/aiplatform/apiv1/aiplatformpb"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sola-scriptura-search-api/internal/metrics"
 	"github.com/sola-scriptura-search-api/internal/models"
 	"github.com/sola-scriptura-search-api/internal/repository"
+	"github.com/sola-scriptura-search-api/pkg/cache"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Ensure VectorSearchRepository implements repository.VectorSearchRepository
@@ -17,18 +26,30 @@ var _ repository.VectorSearchRepository = (*VectorSearchRepository)(nil)
 
 // Config holds Vertex AI Vector Search configuration
 type Config struct {
-	ProjectID            string // GCP project ID
-	Location             string // e.g., "us-central1"
-	IndexEndpointID      string // Deployed index endpoint ID
-	DeployedIndexID      string // The deployed index ID within the endpoint
-	PublicEndpointDomain string // Public endpoint domain for queries (e.g., "123.us-central1-456.vdb.vertexai.goog")
+	ProjectID            string        // GCP project ID
+	Location             string        // e.g., "us-central1"
+	IndexEndpointID      string        // Deployed index endpoint ID
+	DeployedIndexID      string        // The deployed index ID within the endpoint
+	PublicEndpointDomain string        // Public endpoint domain for queries (e.g., "123.us-central1-456.vdb.vertexai.goog")
+	MaxNeighborCount     int           // Upper bound on NeighborCount per query (0 = DefaultMaxNeighborCount)
+	Dimensions           int           // Index vector dimensionality, used by Ping
+	DistanceMeasure      string        // Index distance measure (empty = repository.DistanceCosine)
+	VerseCacheSize       int           // Verses whose details are kept in memory after lookup (0 disables)
+	VerseCacheTTL        time.Duration // How long cached verse details are reused (0 = until evicted)
 }
 
+// DefaultMaxNeighborCount is Vertex AI's documented per-query neighbor limit
+const DefaultMaxNeighborCount = 1000
+
 // VectorSearchRepository implements repository.VectorSearchRepository using Vertex AI Vector Search
 type VectorSearchRepository struct {
 	config      Config
 	matchClient *aiplatform.MatchClient
 	db          *sqlx.DB // Used to look up verse text after getting IDs from Vertex AI
+
+	// Verse details by OSIS id (score unset); verse text and location never change
+	// between index syncs, so popular neighbors skip the PostgreSQL lookup
+	verseCache *cache.LRU[string, models.ScoredVerse]
 }
 
 // NewVectorSearchRepository creates a new Vertex AI vector search repository
@@ -46,10 +67,22 @@ func NewVectorSearchRepository(ctx context.Context, config Config, db *sqlx.DB)
 		return nil, fmt.Errorf("create match client: %w", err)
 	}
 
+	if config.MaxNeighborCount <= 0 {
+		config.MaxNeighborCount = DefaultMaxNeighborCount
+	}
+	if config.DistanceMeasure == "" {
+		config.DistanceMeasure = repository.DistanceCosine
+	}
+	if err := repository.ValidateDistanceMeasure(config.DistanceMeasure); err != nil {
+		matchClient.Close()
+		return nil, err
+	}
+
 	return &VectorSearchRepository{
 		config:      config,
 		matchClient: matchClient,
 		db:          db,
+		verseCache:  cache.New[string, models.ScoredVerse](config.VerseCacheSize, config.VerseCacheTTL),
 	}, nil
 }
 
@@ -62,33 +95,107 @@ func (r *VectorSearchRepository) Close() error {
 }
 
 // SearchVersesByEmbedding performs vector similarity search using Vertex AI Vector Search
-func (r *VectorSearchRepository) SearchVersesByEmbedding(ctx context.Context, embedding []float64, topK int) ([]models.ScoredVerse, error) {
-	// Build the index endpoint resource name
-	indexEndpoint := fmt.Sprintf(
+func (r *VectorSearchRepository) SearchVersesByEmbedding(ctx context.Context, embedding []float64, opts models.VectorSearchOptions) ([]models.ScoredVerse, error) {
+	start := time.Now()
+	verses, err := r.searchVersesByEmbedding(ctx, embedding, opts)
+	metrics.VectorSearchDuration.WithLabelValues("vertex", metrics.Outcome(err)).Observe(metrics.Since(start))
+	return verses, err
+}
+
+// IsRetryable reports whether err is a transient Vertex AI failure (endpoint down,
+// quota exhausted, timeout) that another backend could serve instead
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch st.Code() {
+	case codes.Unavailable, codes.ResourceExhausted, codes.DeadlineExceeded, codes.Aborted, codes.Internal:
+		return true
+	}
+	return false
+}
+
+// Ping issues a single-neighbor FindNeighbors with a unit probe vector, verifying the
+// endpoint, deployed index and credentials without touching PostgreSQL
+func (r *VectorSearchRepository) Ping(ctx context.Context) error {
+	if r.config.Dimensions <= 0 {
+		return fmt.Errorf("index dimensions not configured")
+	}
+
+	// A zero vector has no cosine direction, so probe along the first axis
+	probe := make([]float32, r.config.Dimensions)
+	probe[0] = 1
+
+	_, err := r.matchClient.FindNeighbors(ctx, &aiplatformpb.FindNeighborsRequest{
+		IndexEndpoint:   r.indexEndpoint(),
+		DeployedIndexId: r.config.DeployedIndexID,
+		Queries: []*aiplatformpb.FindNeighborsRequest_Query{
+			{
+				Datapoint:     &aiplatformpb.IndexDatapoint{FeatureVector: probe},
+				NeighborCount: 1,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("find neighbors: %w", err)
+	}
+	return nil
+}
+
+// indexEndpoint returns the index endpoint resource name
+func (r *VectorSearchRepository) indexEndpoint() string {
+	return fmt.Sprintf(
 		"projects/%s/locations/%s/indexEndpoints/%s",
 		r.config.ProjectID,
 		r.config.Location,
 		r.config.IndexEndpointID,
 	)
+}
+
+func (r *VectorSearchRepository) searchVersesByEmbedding(ctx context.Context, embedding []float64, opts models.VectorSearchOptions) ([]models.ScoredVerse, error) {
+	filter := opts.Filter
+
+	// Resolve the filter to a book list to guard the results against stale restricts
+	books, ok, err := r.resolveBooks(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("resolve book filter: %w", err)
+	}
+	if !ok {
+		return []models.ScoredVerse{}, nil
+	}
 
 	// Convert embedding to float32
 	featureVector := make([]float32, len(embedding))
 	for i, v := range embedding {
 		featureVector[i] = float32(v)
 	}
+	datapoint := queryDatapoint(featureVector, filter)
+
+	// Vertex has no offset, so fetch through the end of the page and slice below
+	// It also rejects the whole query above its neighbor limit, so clamp rather than fail
+	neighborCount := opts.TopK + opts.Offset
+	if neighborCount > r.config.MaxNeighborCount {
+		log.Printf("Vertex: clamping NeighborCount %d to max %d", neighborCount, r.config.MaxNeighborCount)
+		neighborCount = r.config.MaxNeighborCount
+	}
 
 	// Build the FindNeighbors request
 	req := &aiplatformpb.FindNeighborsRequest{
-		IndexEndpoint:   indexEndpoint,
+		IndexEndpoint:   r.indexEndpoint(),
 		DeployedIndexId: r.config.DeployedIndexID,
 		Queries: []*aiplatformpb.FindNeighborsRequest_Query{
 			{
-				Datapoint: &aiplatformpb.IndexDatapoint{
-					FeatureVector: featureVector,
-				},
-				NeighborCount: int32(topK),
+				Datapoint:     datapoint,
+				NeighborCount: int32(neighborCount),
+				// Caps neighbors sharing a crowding tag; datapoints are tagged with their book
+				PerCrowdingAttributeNeighborCount: int32(opts.MaxPerBook),
 			},
 		},
+		ReturnFullDatapoint: opts.IncludeEmbeddings,
 	}
 
 	// Execute the search
@@ -103,39 +210,243 @@ func (r *VectorSearchRepository) SearchVersesByEmbedding(ctx context.Context, em
 	}
 
 	neighbors := resp.NearestNeighbors[0].Neighbors
+	if opts.Offset >= len(neighbors) {
+		return []models.ScoredVerse{}, nil
+	}
+	neighbors = neighbors[opts.Offset:]
 
 	// Collect verse IDs for batch lookup
 	verseIDs := make([]string, len(neighbors))
 	scoreMap := make(map[string]float64, len(neighbors))
+	distanceMap := make(map[string]float64, len(neighbors))
+	embeddingMap := make(map[string][]float64)
 
 	for i, neighbor := range neighbors {
 		verseID := neighbor.Datapoint.DatapointId
 		verseIDs[i] = verseID
-		// Vertex AI returns distance, convert to similarity score
-		// For cosine distance: similarity = 1 - distance
-		scoreMap[verseID] = float64(1 - neighbor.Distance)
+		// Neighbors arrive closest first, so a repeated id keeps its first (best) distance
+		if _, seen := scoreMap[verseID]; seen {
+			continue
+		}
+		// Vertex AI returns a distance in the index's measure; normalize to [0, 1] similarity
+		scoreMap[verseID] = repository.NormalizeScore(r.config.DistanceMeasure, neighbor.Distance)
+		distanceMap[verseID] = neighbor.Distance
+		if opts.IncludeEmbeddings {
+			embeddingMap[verseID] = float64Slice(neighbor.Datapoint.FeatureVector)
+		}
 	}
 
 	// Look up verse details from PostgreSQL
-	results, err := r.lookupVerses(ctx, verseIDs, scoreMap)
+	results, err := r.lookupVerses(ctx, verseIDs, scoreMap, books)
 	if err != nil {
 		return nil, fmt.Errorf("lookup verses: %w", err)
 	}
+	kept := results[:0]
+	for _, v := range results {
+		// Guard the numeric restrict the same way lookupVerses guards the book restrict
+		if !filter.InBookOrderRange(v.BookOrder) {
+			log.Printf("Warning: dropping %s: book order %d is outside the search restrict", v.VerseID, v.BookOrder)
+			continue
+		}
+		v.Embedding = embeddingMap[v.VerseID]
+		v.DistanceMeasure, v.Distance = r.config.DistanceMeasure, distanceMap[v.VerseID]
+		kept = append(kept, v)
+	}
 
-	return results, nil
+	return kept, nil
 }
 
-// lookupVerses retrieves verse details from PostgreSQL given a list of verse IDs
-func (r *VectorSearchRepository) lookupVerses(ctx context.Context, verseIDs []string, scoreMap map[string]float64) ([]models.ScoredVerse, error) {
+// queryDatapoint builds the query datapoint, restricting to filter's books and testaments if any
+// The "testament" namespace requires datapoints exported/upserted with a testament restrict
+func queryDatapoint(featureVector []float32, filter models.VerseFilter) *aiplatformpb.IndexDatapoint {
+	datapoint := &aiplatformpb.IndexDatapoint{
+		FeatureVector: featureVector,
+	}
+	if len(filter.Books) > 0 {
+		datapoint.Restricts = append(datapoint.Restricts, &aiplatformpb.IndexDatapoint_Restriction{
+			Namespace: "book",
+			AllowList: filter.Books,
+		})
+	}
+	if len(filter.Testaments) > 0 {
+		datapoint.Restricts = append(datapoint.Restricts, &aiplatformpb.IndexDatapoint_Restriction{
+			Namespace: "testament",
+			AllowList: filter.Testaments,
+		})
+	}
+	// The numeric "book_order" namespace pushes range filters into the index
+	if filter.MinBookOrder > 0 {
+		datapoint.NumericRestricts = append(datapoint.NumericRestricts, bookOrderRestrict(filter.MinBookOrder, aiplatformpb.IndexDatapoint_NumericRestriction_GREATER_EQUAL))
+	}
+	if filter.MaxBookOrder > 0 {
+		datapoint.NumericRestricts = append(datapoint.NumericRestricts, bookOrderRestrict(filter.MaxBookOrder, aiplatformpb.IndexDatapoint_NumericRestriction_LESS_EQUAL))
+	}
+	return datapoint
+}
+
+// bookOrderRestrict builds a numeric restrict comparing book_order against value
+func bookOrderRestrict(value int, op aiplatformpb.IndexDatapoint_NumericRestriction_Operator) *aiplatformpb.IndexDatapoint_NumericRestriction {
+	return &aiplatformpb.IndexDatapoint_NumericRestriction{
+		Namespace: "book_order",
+		Value:     &aiplatformpb.IndexDatapoint_NumericRestriction_ValueInt{ValueInt: int64(value)},
+		Op:        op,
+	}
+}
+
+// resolveBooks collapses filter into the OSIS book ids to pass as the "book" restrict.
+// A nil list means no restriction; ok is false when the filter matches no books.
+func (r *VectorSearchRepository) resolveBooks(ctx context.Context, filter models.VerseFilter) ([]string, bool, error) {
+	if len(filter.Testaments) == 0 {
+		return filter.Books, true, nil
+	}
+
+	var books []string
+	if err := r.db.SelectContext(ctx, &books, `
+		SELECT osis_id FROM api.books
+		WHERE testament = ANY($1)
+		ORDER BY book_order
+	`, pq.Array(filter.Testaments)); err != nil {
+		return nil, false, err
+	}
+
+	if len(filter.Books) > 0 {
+		requested := make(map[string]bool, len(filter.Books))
+		for _, b := range filter.Books {
+			requested[b] = true
+		}
+		kept := books[:0]
+		for _, b := range books {
+			if requested[b] {
+				kept = append(kept, b)
+			}
+		}
+		books = kept
+	}
+
+	return books, len(books) > 0, nil
+}
+
+// lookupVerses retrieves verse details given a list of verse IDs, from the verse cache
+// where possible and otherwise from PostgreSQL
+// When books is non-empty, verses whose looked-up book is outside it are dropped: the
+// restrict was applied by Vertex, but a stale restrict or index/DB divergence could
+// otherwise leak out-of-scope results
+func (r *VectorSearchRepository) lookupVerses(ctx context.Context, verseIDs []string, scoreMap map[string]float64, books []string) ([]models.ScoredVerse, error) {
 	if len(verseIDs) == 0 {
 		return []models.ScoredVerse{}, nil
 	}
 
+	verseMap := make(map[string]models.ScoredVerse, len(verseIDs))
+	var uncached []string
+	for _, id := range verseIDs {
+		if v, ok := r.verseCache.Get(id); ok {
+			verseMap[id] = v
+		} else {
+			uncached = append(uncached, id)
+		}
+	}
+	if len(uncached) > 0 {
+		if err := r.queryVerses(ctx, uncached, verseMap); err != nil {
+			return nil, err
+		}
+		for _, id := range uncached {
+			if v, ok := verseMap[id]; ok {
+				r.verseCache.Set(id, v)
+			}
+		}
+	}
+
+	allowedBooks := make(map[string]bool, len(books))
+	for _, b := range books {
+		allowedBooks[b] = true
+	}
+
+	// Preserve the order from Vertex AI (sorted by relevance)
+	results := make([]models.ScoredVerse, 0, len(verseIDs))
+	for _, id := range verseIDs {
+		v, ok := verseMap[id]
+		if !ok {
+			continue
+		}
+		if len(allowedBooks) > 0 && !allowedBooks[v.Book] {
+			log.Printf("Warning: dropping %s: book %s is outside the search restrict", v.VerseID, v.Book)
+			continue
+		}
+		v.Score = scoreMap[id]
+		results = append(results, v)
+	}
+
+	return results, nil
+}
+
+// lookupBatchSize caps the verse ids per mv_verses_search query, keeping IN lists
+// small when large topK or over-fetching returns hundreds of neighbors
+const lookupBatchSize = 100
+
+// queryVerses adds the PostgreSQL details of verseIDs to verseMap
+// Ids are looked up in concurrent batches of lookupBatchSize
+func (r *VectorSearchRepository) queryVerses(ctx context.Context, verseIDs []string, verseMap map[string]models.ScoredVerse) error {
+	var batches [][]string
+	for start := 0; start < len(verseIDs); start += lookupBatchSize {
+		batches = append(batches, verseIDs[start:min(start+lookupBatchSize, len(verseIDs))])
+	}
+
+	var wg sync.WaitGroup
+	found := make([][]models.ScoredVerse, len(batches))
+	errs := make([]error, len(batches))
+	for i, batch := range batches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found[i], errs[i] = r.queryVerseBatch(ctx, batch)
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	for _, verses := range found {
+		for _, v := range verses {
+			verseMap[v.VerseID] = v
+		}
+	}
+
+	// The materialized view lags the base tables until refreshed, so look up any
+	// neighbors it lacks in api.verses rather than silently returning fewer results
+	var missing []string
+	for _, id := range verseIDs {
+		if _, ok := verseMap[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		if err := r.lookupBaseVerses(ctx, missing, verseMap); err != nil {
+			return err
+		}
+		var unresolved []string
+		for _, id := range missing {
+			if _, ok := verseMap[id]; ok {
+				metrics.MissingVerses.WithLabelValues("base_table").Inc()
+			} else {
+				metrics.MissingVerses.WithLabelValues("dropped").Inc()
+				unresolved = append(unresolved, id)
+			}
+		}
+		log.Printf("Warning: %d of %d neighbors missing from mv_verses_search (%d dropped: %v); refresh the view or re-sync the index",
+			len(missing), len(verseIDs), len(unresolved), unresolved)
+	}
+	return nil
+}
+
+// queryVerseBatch returns the mv_verses_search details of verseIDs
+func (r *VectorSearchRepository) queryVerseBatch(ctx context.Context, verseIDs []string) ([]models.ScoredVerse, error) {
 	// Use the materialized view for verse lookup
 	query, args, err := sqlx.In(`
-		SELECT verse_id, book, chapter, verse, text
-		FROM api_views.mv_verses_search
-		WHERE verse_id IN (?)
+		SELECT mv.verse_id, mv.book, mv.chapter, mv.verse, mv.text, b.book_order
+		FROM api_views.mv_verses_search mv
+		JOIN api.books b ON b.osis_id = mv.book
+		WHERE mv.verse_id IN (?)
 	`, verseIDs)
 	if err != nil {
 		return nil, fmt.Errorf("build IN query: %w", err)
@@ -150,28 +461,52 @@ func (r *VectorSearchRepository) lookupVerses(ctx context.Context, verseIDs []st
 	}
 	defer rows.Close()
 
-	// Create a map for ordering results by score
-	verseMap := make(map[string]models.ScoredVerse)
+	verses := make([]models.ScoredVerse, 0, len(verseIDs))
 	for rows.Next() {
 		var v models.ScoredVerse
-		if err := rows.Scan(&v.VerseID, &v.Book, &v.Chapter, &v.Verse, &v.Text); err != nil {
+		if err := rows.Scan(&v.VerseID, &v.Book, &v.Chapter, &v.Verse, &v.Text, &v.BookOrder); err != nil {
 			return nil, fmt.Errorf("scan verse: %w", err)
 		}
-		v.Score = scoreMap[v.VerseID]
-		verseMap[v.VerseID] = v
+		verses = append(verses, v)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate verses: %w", err)
 	}
+	return verses, nil
+}
 
-	// Preserve the order from Vertex AI (sorted by relevance)
-	results := make([]models.ScoredVerse, 0, len(verseIDs))
-	for _, id := range verseIDs {
-		if v, ok := verseMap[id]; ok {
-			results = append(results, v)
+// lookupBaseVerses adds verses absent from the materialized view to verseMap from api.verses
+func (r *VectorSearchRepository) lookupBaseVerses(ctx context.Context, verseIDs []string, verseMap map[string]models.ScoredVerse) error {
+	rows, err := r.db.QueryxContext(ctx, `
+		SELECT v.osis_verse_id, b.osis_id, v.chapter, v.verse, v.text, b.book_order
+		FROM api.verses v
+		JOIN api.books b ON v.book_id = b.id
+		WHERE v.osis_verse_id = ANY($1)
+	`, pq.Array(verseIDs))
+	if err != nil {
+		return fmt.Errorf("query base verses: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v models.ScoredVerse
+		if err := rows.Scan(&v.VerseID, &v.Book, &v.Chapter, &v.Verse, &v.Text, &v.BookOrder); err != nil {
+			return fmt.Errorf("scan base verse: %w", err)
 		}
+		verseMap[v.VerseID] = v
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate base verses: %w", err)
 	}
+	return nil
+}
 
-	return results, nil
+// float64Slice converts a Vertex AI feature vector to []float64
+func float64Slice(f32 []float32) []float64 {
+	f64 := make([]float64, len(f32))
+	for i, v := range f32 {
+		f64[i] = float64(v)
+	}
+	return f64
 }
@@ -0,0 +1,76 @@
+package vertex
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sola-scriptura-search-api/internal/models"
+	"github.com/sola-scriptura-search-api/pkg/cache"
+)
+
+func TestQueryDatapointTestamentRestrict(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter models.VerseFilter
+		want   map[string][]string
+	}{
+		{"no filter", models.VerseFilter{}, map[string][]string{}},
+		{"testament only", models.VerseFilter{Testaments: []string{models.TestamentNew}},
+			map[string][]string{"testament": {"NT"}}},
+		{"books and testament", models.VerseFilter{Books: []string{"Ps"}, Testaments: []string{models.TestamentOld}},
+			map[string][]string{"book": {"Ps"}, "testament": {"OT"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dp := queryDatapoint([]float32{1}, tt.filter)
+			got := make(map[string][]string)
+			for _, r := range dp.Restricts {
+				got[r.Namespace] = r.AllowList
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("restricts = %v, want %v", got, tt.want)
+			}
+			for namespace, allow := range tt.want {
+				if len(got[namespace]) != len(allow) || got[namespace][0] != allow[0] {
+					t.Errorf("%s restrict = %v, want %v", namespace, got[namespace], allow)
+				}
+			}
+		})
+	}
+}
+
+func TestLookupVersesDropsOutOfRestrictBooks(t *testing.T) {
+	// Cached rows stand in for PostgreSQL, so no query is issued
+	r := &VectorSearchRepository{verseCache: cache.New[string, models.ScoredVerse](10, time.Hour)}
+	r.verseCache.Set("Rom.8.28", models.ScoredVerse{VerseID: "Rom.8.28", Book: "Rom"})
+	r.verseCache.Set("John.3.16", models.ScoredVerse{VerseID: "John.3.16", Book: "John"})
+	ids := []string{"Rom.8.28", "John.3.16"}
+	scores := map[string]float64{"Rom.8.28": 0.9, "John.3.16": 0.8}
+
+	tests := []struct {
+		name  string
+		books []string
+		want  []string
+	}{
+		{"no restrict", nil, []string{"Rom.8.28", "John.3.16"}},
+		{"matching restrict", []string{"Rom", "John"}, []string{"Rom.8.28", "John.3.16"}},
+		{"mismatched book dropped", []string{"Rom"}, []string{"Rom.8.28"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verses, err := r.lookupVerses(context.Background(), ids, scores, tt.books)
+			if err != nil {
+				t.Fatalf("lookupVerses: %v", err)
+			}
+			if len(verses) != len(tt.want) {
+				t.Fatalf("got %v, want %v", verses, tt.want)
+			}
+			for i, v := range verses {
+				if v.VerseID != tt.want[i] || v.Score != scores[v.VerseID] {
+					t.Errorf("verse %d = %s (%.1f), want %s (%.1f)", i, v.VerseID, v.Score, tt.want[i], scores[tt.want[i]])
+				}
+			}
+		})
+	}
+}
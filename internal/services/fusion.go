@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sola-scriptura-search-api/internal/logging"
+	"github.com/sola-scriptura-search-api/internal/models"
+)
+
+// rrfK dampens the contribution of top ranks in reciprocal rank fusion (the usual 60)
+const rrfK = 60
+
+// fusionTopicVerseLimit is how many verses each matched topic contributes to fusion
+const fusionTopicVerseLimit = 10
+
+// fusionList is one weighted ranked input to reciprocal rank fusion
+type fusionList struct {
+	name      string // One of the models.FusionList names
+	citations []models.Citation
+	weight    float64
+}
+
+// fuseVerses ranks semantic hits together with the matched topics' verses (Fusion)
+// and full-text hits (Lexical) using RRF. A verse found several ways accumulates each
+// contribution, scaled by the request's weights. Fusion does not paginate;
+// results are the top req.Limit fused verses.
+func (s *VectorSearchService) fuseVerses(ctx context.Context, scored, lexical []models.ScoredVerse, topics []models.ScoredTopic, req models.SearchRequest) ([]models.Citation, error) {
+	opts := req.VerseSearchOptions
+	opts.Offset = 0
+
+	verseWeight, topicWeight, lexicalWeight := req.FusionWeights()
+	semantic := citationsFromScored(scored)
+	if opts.Explain {
+		explainScored(semantic, scored)
+	}
+	lists := []fusionList{{name: models.FusionListSemantic, citations: semantic, weight: verseWeight}}
+	if req.Fusion {
+		lists = append(lists, fusionList{name: models.FusionListTopic, citations: s.topicVerseList(ctx, topics, opts, req.MaxTier), weight: topicWeight})
+	}
+	if req.Lexical {
+		// relevance_score stays a semantic similarity; ts_rank is not comparable to it
+		lexicalCitations := citationsFromScored(lexical)
+		if opts.Explain {
+			explainScored(lexicalCitations, lexical)
+		}
+		for i := range lexicalCitations {
+			lexicalCitations[i].RelevanceScore = nil
+		}
+		lists = append(lists, fusionList{name: models.FusionListLexical, citations: lexicalCitations, weight: lexicalWeight})
+	}
+
+	fused := reciprocalRankFusion(opts.Explain, lists...)
+	return s.finishCitations(ctx, page(fused, 0, req.Limit), opts)
+}
+
+// topicVerseList flattens the matched topics' verses into one ranked list: topics in
+// score order, each topic's verses by importance tier, duplicates keeping their first rank.
+// A lookup failure is logged and yields no list so fusion degrades to semantic ranking.
+func (s *VectorSearchService) topicVerseList(ctx context.Context, topics []models.ScoredTopic, opts models.VerseSearchOptions, maxTier int) []models.Citation {
+	filter, ok := s.scopedFilter(opts.VerseFilter())
+	if !ok {
+		return nil
+	}
+
+	ids := make([]string, len(topics))
+	for i, topic := range topics {
+		ids[i] = topic.TopicID
+	}
+	byTopic, err := s.topicRepo.GetTopicsVerses(ctx, ids, models.TopicVerseOptions{
+		Limit:   fusionTopicVerseLimit,
+		MaxTier: maxTier,
+		Filter:  filter,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Warn("topic verses for fusion failed", "topics", len(ids), "error", err)
+		return nil
+	}
+
+	var list []models.Citation
+	seen := make(map[string]bool)
+	for _, topic := range topics {
+		for _, v := range byTopic[topic.TopicID] {
+			if !seen[v.VerseID] {
+				seen[v.VerseID] = true
+				list = append(list, v)
+			}
+		}
+	}
+	return list
+}
+
+// reciprocalRankFusion merges ranked lists by OSIS id, scoring each verse
+// sum(weight / (rrfK + rank)) over the lists it appears in. Ties keep the order of
+// first appearance, so the first (semantic) list wins and supplies the citation.
+// explain records each list's contribution in the citation's explanation.
+func reciprocalRankFusion(explain bool, lists ...fusionList) []models.Citation {
+	type fusedCitation struct {
+		citation      models.Citation
+		score         float64
+		contributions []models.FusionContribution
+	}
+	byID := make(map[string]*fusedCitation)
+	var order []*fusedCitation
+
+	for _, list := range lists {
+		for rank, c := range list.citations {
+			f, ok := byID[c.VerseID]
+			if !ok {
+				f = &fusedCitation{citation: c}
+				byID[c.VerseID] = f
+				order = append(order, f)
+			}
+			contribution := list.weight / float64(rrfK+rank+1)
+			f.score += contribution
+			if explain {
+				f.contributions = append(f.contributions, models.FusionContribution{
+					List: list.name, Rank: rank + 1, Weight: list.weight, Score: contribution,
+				})
+				// Keep the first list's explanation, adding a lexical hit's text rank
+				switch {
+				case c.Explain == nil:
+				case f.citation.Explain == nil:
+					f.citation.Explain = c.Explain
+				case f.citation.Explain.TextRank == nil:
+					f.citation.Explain.TextRank = c.Explain.TextRank
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].score > order[j].score
+	})
+
+	fused := make([]models.Citation, len(order))
+	for i, f := range order {
+		score := f.score
+		fused[i] = f.citation
+		fused[i].FusionScore = &score
+		if explain {
+			explanation := models.ScoreExplanation{}
+			if f.citation.Explain != nil {
+				explanation = *f.citation.Explain
+			}
+			explanation.Fusion = f.contributions
+			fused[i].Explain = &explanation
+		}
+	}
+	return fused
+}
@@ -0,0 +1,560 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sola-scriptura-search-api/internal/logging"
+	"github.com/sola-scriptura-search-api/internal/models"
+	"github.com/sola-scriptura-search-api/internal/repository"
+	"github.com/sola-scriptura-search-api/pkg/osis"
+)
+
+// ErrInvalidReference is returned when a reference-mode query is not a list of OSIS verse ids
+var ErrInvalidReference = errors.New("invalid verse reference")
+
+// ErrCrossBookRange is returned for a passage range that starts and ends in different books
+var ErrCrossBookRange = errors.New("passage ranges cannot span books")
+
+// ErrPassageTooLong is returned for a passage longer than models.MaxPassageVerses
+var ErrPassageTooLong = fmt.Errorf("passage exceeds %d verses", models.MaxPassageVerses)
+
+// Topic card selection used by hybrid search
+const (
+	topicCardMinScore   = 0.9
+	topicCardVerseLimit = 10
+)
+
+// parseVerseID normalizes a single-verse reference such as "John 3:16", "1 Cor 13.4"
+// or "John.3.16" to its OSIS id, returning ErrInvalidReference if it is not one verse
+func parseVerseID(ref string) (string, error) {
+	v, err := osis.ParseVerse(ref)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidReference, err)
+	}
+	return v.OSIS(), nil
+}
+
+// page returns the window of items starting at offset holding at most limit items
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return items[:0]
+	}
+	items = items[offset:]
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// streamChunkSize is how many citations StreamSearch expands per batch before emitting
+const streamChunkSize = 5
+
+// StreamSearch runs a semantic search and passes each citation to emit in rank order
+// The vector hits are resolved first, then expanded streamChunkSize at a time, so early
+// results are sent while later ones are still being expanded. Results are grouped over
+// the whole list before expansion, matching SearchVersesCitations.
+// Stops at the first emit error or when ctx is cancelled.
+func (s *VectorSearchService) StreamSearch(ctx context.Context, query string, topK int, opts models.VerseSearchOptions, emit func(models.Citation) error) error {
+	scored, err := s.SearchVerses(ctx, query, topK, opts)
+	if err != nil {
+		return err
+	}
+	citations := citationsFromScored(scored)
+	if opts.Explain {
+		explainScored(citations, scored)
+	}
+	if opts.GroupAdjacent {
+		citations = groupAdjacent(citations)
+	}
+
+	for start := 0; start < len(citations); start += streamChunkSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		chunk, err := s.expand(ctx, citations[start:min(start+streamChunkSize, len(citations))], opts)
+		if err != nil {
+			return err
+		}
+		for _, citation := range chunk {
+			if err := emit(citation); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Search dispatches a unified search request to the path for its mode
+// Limits and options are expected to be validated by the caller
+func (s *VectorSearchService) Search(ctx context.Context, req models.SearchRequest) (*models.SearchResponse, error) {
+	resp := &models.SearchResponse{Mode: req.Mode, Query: req.Query, Limit: req.Limit, Offset: req.Offset}
+	if resp.Mode == "" {
+		resp.Mode = models.SearchModeSemantic
+	}
+
+	// Ask for one extra result to learn whether another page exists
+	req.Limit++
+
+	var err error
+	switch resp.Mode {
+	case models.SearchModeSemantic:
+		resp.Results, err = s.SearchVersesCitations(ctx, req.Query, req.Limit, req.VerseSearchOptions)
+	case models.SearchModeKeyword:
+		resp.Results, err = s.SearchVersesText(ctx, req.Query, req.Limit, req.VerseSearchOptions)
+	case models.SearchModeHybrid:
+		err = s.hybridSearch(ctx, req, resp)
+	case models.SearchModeReference:
+		resp.Results, err = s.LookupReferences(ctx, req.Query, req.Limit, req.VerseSearchOptions)
+	default:
+		return nil, fmt.Errorf("unknown search mode %q", resp.Mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) > resp.Limit {
+		resp.Results = resp.Results[:resp.Limit]
+		resp.HasMore = true
+	}
+	return resp, nil
+}
+
+// hybridSearch fills resp with semantic verse results, keyword topics and topic cards
+// Verse, topic and (if Lexical) full-text search run concurrently, so latency is bounded
+// by the slowest. Topic and lexical failures are logged and degrade to semantic results.
+func (s *VectorSearchService) hybridSearch(ctx context.Context, req models.SearchRequest, resp *models.SearchResponse) error {
+	verseOpts := req.VerseSearchOptions
+	if req.Fusion || req.Lexical {
+		verseOpts.Offset = 0 // Fusion ranks from the top and does not paginate
+	}
+
+	var (
+		wg         sync.WaitGroup
+		scored     []models.ScoredVerse
+		verseErr   error
+		topics     []models.ScoredTopic
+		topicErr   error
+		lexical    []models.ScoredVerse
+		lexicalErr error
+	)
+	if req.Lexical {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lexical, lexicalErr = s.searchTextScored(ctx, req.Query, req.Limit, verseOpts)
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		// Embed the query once and reuse it for every embedding-based stage
+		embedding, err := s.EmbedQuery(ctx, req.Query)
+		if err != nil {
+			verseErr = err
+			return
+		}
+		scored, verseErr = s.SearchVersesByEmbeddingPrecomputed(ctx, embedding, req.Limit, verseOpts)
+	}()
+	go func() {
+		defer wg.Done()
+		topics, topicErr = s.SearchTopics(ctx, req.Query, req.TopicLimit, req.TopicSearchOptions)
+	}()
+	wg.Wait()
+
+	if verseErr != nil {
+		return verseErr
+	}
+	if topicErr != nil {
+		logging.FromContext(ctx).Warn("topic search failed", "error", topicErr)
+		topics = []models.ScoredTopic{}
+	}
+	resp.Topics = topics
+	if lexicalErr != nil {
+		logging.FromContext(ctx).Warn("lexical search failed", "error", lexicalErr)
+		lexical = nil
+	}
+
+	var err error
+	if req.Fusion || req.Lexical {
+		resp.Results, err = s.fuseVerses(ctx, scored, lexical, topics, req)
+	} else {
+		resp.Results, err = s.toCitations(ctx, scored, verseOpts)
+	}
+	if err != nil {
+		return err
+	}
+
+	// One topic card per distinct concept with a strong match
+	if len(topics) > 0 {
+		verseOpts := models.TopicVerseOptions{Limit: topicCardVerseLimit, MaxTier: req.MaxTier}
+		cards, err := s.GetTopicCards(ctx, req.Query, topics, topicCardMinScore, verseOpts, s.maxTopicCards)
+		if err != nil {
+			logging.FromContext(ctx).Warn("topic card fetch failed", "error", err)
+		}
+		if len(cards) > 0 {
+			resp.TopicCards = cards
+			resp.TopicCard = &cards[0]
+		}
+	}
+	return nil
+}
+
+// SearchVersesText performs full-text search over verse text within the allowed scope
+func (s *VectorSearchService) SearchVersesText(ctx context.Context, query string, topK int, opts models.VerseSearchOptions) ([]models.Citation, error) {
+	verses, err := s.searchTextScored(ctx, query, topK, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.toCitations(ctx, verses, opts)
+}
+
+// searchTextScored runs full-text search within the allowed scope, returning the
+// requested page of text-ranked verses
+func (s *VectorSearchService) searchTextScored(ctx context.Context, query string, topK int, opts models.VerseSearchOptions) ([]models.ScoredVerse, error) {
+	filter, ok := s.scopedFilter(opts.VerseFilter())
+	if !ok {
+		return []models.ScoredVerse{}, nil
+	}
+
+	fetchK := topK + opts.Offset
+	if hasPostFilters(opts) {
+		fetchK *= postFilterOverFetch
+	}
+
+	verses, err := s.verseRepo.SearchText(ctx, query, fetchK, filter)
+	if err != nil {
+		return nil, err
+	}
+	return page(postRetrieval(verses, len(verses), opts), opts.Offset, topK), nil
+}
+
+// SearchPhrase finds verses containing a phrase (or, if exact, the literal text)
+// within the allowed scope, independent of embeddings
+func (s *VectorSearchService) SearchPhrase(ctx context.Context, phrase string, exact bool, topK int, opts models.VerseSearchOptions) ([]models.Citation, error) {
+	filter, ok := s.scopedFilter(opts.VerseFilter())
+	if !ok {
+		return []models.Citation{}, nil
+	}
+
+	fetchK := topK + opts.Offset
+	if hasPostFilters(opts) {
+		fetchK *= postFilterOverFetch
+	}
+
+	verses, err := s.verseRepo.SearchPhrase(ctx, phrase, exact, fetchK, filter)
+	if err != nil {
+		return nil, err
+	}
+	verses = page(postRetrieval(verses, len(verses), opts), opts.Offset, topK)
+	return s.toCitations(ctx, verses, opts)
+}
+
+// LookupReferences returns the verses named by a list of references in request order
+// References may be OSIS ids or human forms ("John 3:16") separated by commas,
+// semicolons or newlines; whitespace also separates OSIS ids. Unknown or out-of-scope
+// verses are omitted. Returns ErrInvalidReference if any reference is malformed.
+func (s *VectorSearchService) LookupReferences(ctx context.Context, query string, limit int, opts models.VerseSearchOptions) ([]models.Citation, error) {
+	ids, err := parseReferenceList(query)
+	if err != nil {
+		return nil, err
+	}
+	ids = page(ids, opts.Offset, limit)
+
+	filter, ok := s.scopedFilter(opts.VerseFilter())
+	if !ok || len(ids) == 0 {
+		return []models.Citation{}, nil
+	}
+
+	verses, err := s.verseRepo.GetVerses(ctx, ids, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	citations := make([]models.Citation, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if v, ok := verses[id]; ok && !seen[id] {
+			seen[id] = true
+			citations = append(citations, v)
+		}
+	}
+	return s.expand(ctx, citations, opts)
+}
+
+// parseReferenceList splits a reference list into OSIS verse ids
+// A chunk that does not parse as one reference is retried as whitespace-separated
+// ids, so "John.3.16 Rom.5.8" keeps working alongside "John 3:16, Rom 5:8"
+func parseReferenceList(query string) ([]string, error) {
+	chunks := strings.FieldsFunc(query, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	})
+
+	var ids []string
+	for _, chunk := range chunks {
+		chunk = strings.TrimSpace(chunk)
+		if chunk == "" {
+			continue
+		}
+		if id, err := parseVerseID(chunk); err == nil {
+			ids = append(ids, id)
+			continue
+		}
+		for _, field := range strings.Fields(chunk) {
+			id, err := parseVerseID(field)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidReference, chunk)
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// GetVerse returns a single verse by OSIS id or human reference ("John 3:16")
+// Returns ErrInvalidReference for a malformed reference and repository.ErrNotFound if
+// the verse does not exist or is outside the allowed scope
+func (s *VectorSearchService) GetVerse(ctx context.Context, ref string) (*models.Citation, error) {
+	osisID, err := parseVerseID(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, ok := s.scopedFilter(models.VerseFilter{})
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+
+	verses, err := s.verseRepo.GetVerses(ctx, []string{osisID}, filter)
+	if err != nil {
+		return nil, err
+	}
+	verse, ok := verses[osisID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &verse, nil
+}
+
+// GetVerses returns the verses of refs (OSIS ids or human single-verse references) in
+// the order requested, each once; unknown and out-of-scope verses are omitted. Returns
+// ErrInvalidReference naming the first reference that is not a single verse.
+func (s *VectorSearchService) GetVerses(ctx context.Context, refs []string) ([]models.Citation, error) {
+	ids := make([]string, len(refs))
+	for i, ref := range refs {
+		id, err := parseVerseID(ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+		ids[i] = id
+	}
+
+	filter, ok := s.scopedFilter(models.VerseFilter{})
+	if !ok || len(ids) == 0 {
+		return []models.Citation{}, nil
+	}
+	verses, err := s.verseRepo.GetVerses(ctx, ids, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	citations := make([]models.Citation, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if v, ok := verses[id]; ok && !seen[id] {
+			seen[id] = true
+			citations = append(citations, v)
+		}
+	}
+	return citations, nil
+}
+
+// RandomVerse returns a random verse within the allowed scope, optionally limited to a
+// testament and/or a book given by OSIS id or name ("Rom", "Romans"). Returns
+// ErrInvalidReference for an unknown book and repository.ErrNotFound if nothing matches.
+func (s *VectorSearchService) RandomVerse(ctx context.Context, testament, book string) (*models.Citation, error) {
+	var requested models.VerseFilter
+	if testament != "" {
+		requested.Testaments = []string{testament}
+	}
+	if book != "" {
+		b, err := osis.LookupBook(book)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidReference, err)
+		}
+		requested.Books = []string{b.ID}
+	}
+
+	filter, ok := s.scopedFilter(requested)
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return s.verseRepo.RandomVerse(ctx, filter)
+}
+
+// ListBooks returns the books within the deployment scope in canonical order, named
+// from the OSIS book list
+func (s *VectorSearchService) ListBooks(ctx context.Context) ([]models.BookInfo, error) {
+	filter, ok := s.scopedFilter(models.VerseFilter{})
+	if !ok {
+		return []models.BookInfo{}, nil
+	}
+	books, err := s.verseRepo.ListBooks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range books {
+		books[i].Name = books[i].OSISID
+		if b, ok := osis.BookByID(books[i].OSISID); ok {
+			books[i].Name, books[i].Chapters = b.Name, b.Chapters
+		}
+	}
+	return books, nil
+}
+
+// GetPassage returns the verses of a reference such as "Rom.8.28-Rom.8.30", "Rom 8:28-30",
+// "John 3:16-4:2" or "Ps 23" in canonical order. Range ends may cross chapters but not
+// books. Returns ErrInvalidReference, ErrCrossBookRange or ErrPassageTooLong; verses
+// outside the allowed scope are omitted.
+func (s *VectorSearchService) GetPassage(ctx context.Context, ref string) ([]models.Citation, error) {
+	r, err := osis.Parse(ref)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReference, err)
+	}
+	if r.CrossesBooks() {
+		return nil, ErrCrossBookRange
+	}
+
+	filter, ok := s.scopedFilter(models.VerseFilter{})
+	if !ok {
+		return []models.Citation{}, nil
+	}
+
+	verses, err := s.verseRepo.GetRange(ctx, models.VerseRange{
+		Book:         r.Start.Book,
+		StartChapter: r.Start.Chapter,
+		StartVerse:   r.Start.Verse,
+		EndChapter:   r.End.Chapter,
+		EndVerse:     r.End.Verse,
+	}, models.MaxPassageVerses+1, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(verses) > models.MaxPassageVerses {
+		return nil, ErrPassageTooLong
+	}
+	return verses, nil
+}
+
+// VerseAnnotations returns the theological annotations enrichment assigned to a verse
+// Returns ErrInvalidReference for a malformed id and repository.ErrNotFound if the
+// verse does not exist or is outside the allowed scope
+func (s *VectorSearchService) VerseAnnotations(ctx context.Context, ref string) (*models.VerseAnnotations, error) {
+	verse, err := s.GetVerse(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations, err := s.verseRepo.GetAnnotations(ctx, verse.VerseID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.VerseAnnotations{VerseID: verse.VerseID, Annotations: annotations}, nil
+}
+
+// VerseTopics returns up to limit topics a verse belongs to, most important first
+// Returns ErrInvalidReference for a malformed id and repository.ErrNotFound if the
+// verse does not exist or is outside the allowed scope
+func (s *VectorSearchService) VerseTopics(ctx context.Context, ref string, limit int) ([]models.VerseTopic, error) {
+	verse, err := s.GetVerse(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return s.topicRepo.GetVerseTopics(ctx, verse.VerseID, limit)
+}
+
+// CompareVerses returns the cosine similarity of two verses' stored embeddings
+// Errors are prefixed with the offending reference and wrap ErrInvalidReference,
+// repository.ErrNotFound (unknown or out of scope) or repository.ErrNoEmbedding
+func (s *VectorSearchService) CompareVerses(ctx context.Context, refA, refB string) (*models.VerseComparison, error) {
+	var verses [2]*models.Citation
+	var embeddings [2][]float64
+	for i, ref := range []string{refA, refB} {
+		verse, err := s.GetVerse(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+		_, embedding, err := s.verseRepo.GetVerseEmbedding(ctx, verse.VerseID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", verse.VerseID, err)
+		}
+		verses[i], embeddings[i] = verse, embedding
+	}
+
+	return &models.VerseComparison{
+		A:          *verses[0],
+		B:          *verses[1],
+		Similarity: cosineSimilarity(embeddings[0], embeddings[1]),
+	}, nil
+}
+
+// SimilarVerses returns the verses closest in meaning to a seed verse, excluding the seed
+// Uses the seed's stored embedding, so no text is re-embedded. Returns ErrInvalidReference
+// for a malformed id, repository.ErrNotFound for an unknown or out-of-scope verse and
+// repository.ErrNoEmbedding if the verse has not been embedded yet.
+func (s *VectorSearchService) SimilarVerses(ctx context.Context, ref string, limit int) ([]models.Citation, error) {
+	verse, err := s.GetVerse(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	osisID := verse.VerseID
+
+	_, embedding, err := s.verseRepo.GetVerseEmbedding(ctx, osisID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, _ := s.scopedFilter(models.VerseFilter{})
+
+	// The seed is its own nearest neighbor, so fetch one extra
+	verses, err := s.vectorRepo.SearchVersesByEmbedding(ctx, embedding, models.VectorSearchOptions{
+		TopK:   limit + 1,
+		Filter: filter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	similar := make([]models.ScoredVerse, 0, len(verses))
+	for _, v := range verses {
+		if v.VerseID != osisID {
+			similar = append(similar, v)
+		}
+	}
+	sortCanonicalTies(similar)
+	return s.toCitations(ctx, page(similar, 0, limit), models.VerseSearchOptions{})
+}
+
+// CrossReferences returns up to limit cross-reference targets of a verse in canonical order
+// Returns ErrInvalidReference for a malformed id and repository.ErrNotFound if the
+// verse does not exist or is outside the allowed scope
+func (s *VectorSearchService) CrossReferences(ctx context.Context, ref string, limit int) ([]models.Citation, error) {
+	verse, err := s.GetVerse(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	osisID := verse.VerseID
+
+	filter, _ := s.scopedFilter(models.VerseFilter{})
+	refs, err := s.verseRepo.GetCrossReferences(ctx, []string{osisID}, limit, filter)
+	if err != nil {
+		return nil, err
+	}
+	if refs[osisID] == nil {
+		return []models.Citation{}, nil
+	}
+	return refs[osisID], nil
+}
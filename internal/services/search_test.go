@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sola-scriptura-search-api/internal/models"
+	"github.com/sola-scriptura-search-api/internal/repository"
+	pkgconfig "github.com/sola-scriptura-search-api/pkg/schema/config"
+	pkgservices "github.com/sola-scriptura-search-api/pkg/schema/services"
+)
+
+// hybridStageDelay is how long each stubbed hybrid search stage takes
+const hybridStageDelay = 150 * time.Millisecond
+
+// slowEmbedder returns a fixed embedding after delay
+type slowEmbedder struct {
+	delay time.Duration
+	calls atomic.Int32
+}
+
+func (e *slowEmbedder) Embed(ctx context.Context, _ string, _ pkgservices.TaskType) ([]float64, error) {
+	e.calls.Add(1)
+	time.Sleep(e.delay)
+	return []float64{1, 0}, nil
+}
+
+func (e *slowEmbedder) EmbedBatch(ctx context.Context, texts []string, taskType pkgservices.TaskType) ([][]float64, error) {
+	embeddings := make([][]float64, len(texts))
+	for i, text := range texts {
+		embeddings[i], _ = e.Embed(ctx, text, taskType)
+	}
+	return embeddings, nil
+}
+
+// slowTopicRepo answers topic searches after delay, failing with err if set
+type slowTopicRepo struct {
+	repository.TopicRepository
+	delay time.Duration
+	err   error
+	calls atomic.Int32
+}
+
+func (r *slowTopicRepo) SearchByWords(context.Context, []models.QueryTerm, int) ([]models.TopicSearchResult, error) {
+	r.calls.Add(1)
+	time.Sleep(r.delay)
+	return []models.TopicSearchResult{}, r.err
+}
+
+func (r *slowTopicRepo) GetChapterRefs(context.Context, []string, int, models.VerseFilter) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+func newHybridTestService(topicErr error) (*VectorSearchService, *slowEmbedder, *slowTopicRepo) {
+	embedder := &slowEmbedder{delay: hybridStageDelay}
+	topics := &slowTopicRepo{delay: hybridStageDelay, err: topicErr}
+	svc := &VectorSearchService{
+		vectorRepo:    &stubVectorRepo{verses: []models.ScoredVerse{scored("Eph.2.8", 0.9)}},
+		topicRepo:     topics,
+		embeddingsSvc: pkgservices.NewEmbeddingsService(embedder, &pkgconfig.Config{}),
+		topicQuery:    newQueryParser(nil, nil, false),
+	}
+	return svc, embedder, topics
+}
+
+func TestHybridSearchRunsStagesConcurrently(t *testing.T) {
+	svc, embedder, topics := newHybridTestService(nil)
+	req := models.SearchRequest{Mode: models.SearchModeHybrid, Query: "saved by grace", Limit: 5, TopicLimit: 5}
+
+	start := time.Now()
+	resp, err := svc.Search(context.Background(), req)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("hybrid search: %v", err)
+	}
+
+	if embedder.calls.Load() != 1 || topics.calls.Load() != 1 {
+		t.Errorf("embedder called %d times and topic search %d, want 1 each", embedder.calls.Load(), topics.calls.Load())
+	}
+	if len(resp.Results) != 1 || resp.Results[0].VerseID != "Eph.2.8" {
+		t.Errorf("results = %v, want Eph.2.8", resp.Results)
+	}
+	// Sequential stages would take twice the delay; concurrent ones about one delay
+	if elapsed >= 2*hybridStageDelay {
+		t.Errorf("hybrid search took %s, want under %s (stages bounded by the slowest)", elapsed, 2*hybridStageDelay)
+	}
+}
+
+func TestHybridSearchTopicFailureIsNonFatal(t *testing.T) {
+	svc, _, _ := newHybridTestService(errors.New("topic index unavailable"))
+	req := models.SearchRequest{Mode: models.SearchModeHybrid, Query: "saved by grace", Limit: 5, TopicLimit: 5}
+
+	resp, err := svc.Search(context.Background(), req)
+	if err != nil {
+		t.Fatalf("hybrid search: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Topics == nil || len(resp.Topics) != 0 {
+		t.Errorf("results = %v, topics = %v; want verse results and an empty topic list", resp.Results, resp.Topics)
+	}
+}
+
+// stubVerseRepo serves GetVerses from verses, recording the ids it was asked for
+type stubVerseRepo struct {
+	repository.VerseRepository
+	requested []string
+}
+
+func (r *stubVerseRepo) GetVerses(_ context.Context, verseIDs []string, _ models.VerseFilter) (map[string]models.Citation, error) {
+	r.requested = verseIDs
+	// Build the result back to front so nothing about it follows the request order
+	verses := make(map[string]models.Citation, len(verseIDs))
+	for i := len(verseIDs) - 1; i >= 0; i-- {
+		verses[verseIDs[i]] = models.Citation{VerseID: verseIDs[i]}
+	}
+	return verses, nil
+}
+
+func TestGetVersesKeepsRequestOrder(t *testing.T) {
+	// 500 verses in a scrambled, non-canonical order
+	var refs []string
+	for i := 0; i < 500; i++ {
+		n := (i * 7919) % 600
+		refs = append(refs, fmt.Sprintf("Ps.%d.%d", n/4+1, n%4+1))
+	}
+	repo := &stubVerseRepo{}
+	svc := &VectorSearchService{verseRepo: repo}
+
+	citations, err := svc.GetVerses(context.Background(), refs)
+	if err != nil {
+		t.Fatalf("GetVerses: %v", err)
+	}
+	if len(citations) != len(refs) {
+		t.Fatalf("got %d citations, want %d", len(citations), len(refs))
+	}
+	for i, c := range citations {
+		if c.VerseID != refs[i] {
+			t.Fatalf("citation %d = %s, want %s (request order)", i, c.VerseID, refs[i])
+		}
+	}
+	if len(repo.requested) != len(refs) {
+		t.Errorf("repository asked for %d ids, want all %d in one call", len(repo.requested), len(refs))
+	}
+}
+
+// countingWindowRepo serves empty context windows, counting calls
+type countingWindowRepo struct {
+	repository.VerseRepository
+	calls int
+}
+
+func (r *countingWindowRepo) GetVerseWindows(_ context.Context, verseIDs []string, _ int) (map[string][]models.Citation, error) {
+	r.calls++
+	return map[string][]models.Citation{}, nil
+}
+
+func TestStreamSearchEmitsBeforeLastExpansion(t *testing.T) {
+	var verses []models.ScoredVerse
+	for i := 0; i < 3*streamChunkSize; i++ {
+		verses = append(verses, scored(fmt.Sprintf("Ps.119.%d", i+1), 0.9))
+	}
+	windows := &countingWindowRepo{}
+	svc := &VectorSearchService{
+		vectorRepo:    &stubVectorRepo{verses: verses},
+		verseRepo:     windows,
+		embeddingsSvc: pkgservices.NewEmbeddingsService(&slowEmbedder{}, &pkgconfig.Config{}),
+	}
+
+	var emitted, expansionsAtFirst int
+	err := svc.StreamSearch(context.Background(), "thy word", len(verses), models.VerseSearchOptions{ContextWindow: 1}, func(models.Citation) error {
+		if emitted == 0 {
+			expansionsAtFirst = windows.calls
+		}
+		emitted++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("stream search: %v", err)
+	}
+	if emitted != len(verses) {
+		t.Errorf("emitted %d citations, want %d", emitted, len(verses))
+	}
+	if expansionsAtFirst != 1 || windows.calls != 3 {
+		t.Errorf("first emit after %d of %d expansions, want 1 of 3", expansionsAtFirst, windows.calls)
+	}
+}
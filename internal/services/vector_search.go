@@ -2,49 +2,377 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/kljensen/snowball/english"
+	"github.com/sola-scriptura-search-api/internal/config"
+	"github.com/sola-scriptura-search-api/internal/logging"
+	"github.com/sola-scriptura-search-api/internal/metrics"
 	"github.com/sola-scriptura-search-api/internal/models"
 	"github.com/sola-scriptura-search-api/internal/repository"
+	"github.com/sola-scriptura-search-api/pkg/osis"
 	pkgservices "github.com/sola-scriptura-search-api/pkg/schema/services"
+	"golang.org/x/text/unicode/norm"
 )
 
 // VectorSearchService handles semantic search using PostgreSQL with pgvector
 type VectorSearchService struct {
 	vectorRepo    repository.VectorSearchRepository
 	topicRepo     repository.TopicRepository
+	verseRepo     repository.VerseRepository
 	embeddingsSvc *pkgservices.EmbeddingsService
+	allowed       models.VerseFilter // Deployment-wide scope applied to every lookup
+
+	maxTopicCards    int         // Topic cards returned by hybrid search (one per distinct concept)
+	topicQuery       queryParser // Topic query stop words, stemming and synonyms
+	popularityWeight float64     // Ranking boost per unit of verse popularity (0 = disabled)
+	pericopeWindow   int         // Fallback ±N window when a verse has no pericope data
+
+	// Topic card source preference (earlier = preferred)
+	topicSources           []string
+	topicSourcesByCategory map[string][]string
 }
 
 // NewVectorSearchService creates a new vector search service
 func NewVectorSearchService(
 	vectorRepo repository.VectorSearchRepository,
 	topicRepo repository.TopicRepository,
+	verseRepo repository.VerseRepository,
 	embeddingsSvc *pkgservices.EmbeddingsService,
 ) *VectorSearchService {
+	cfg := config.GetConfig()
 	return &VectorSearchService{
 		vectorRepo:    vectorRepo,
 		topicRepo:     topicRepo,
+		verseRepo:     verseRepo,
 		embeddingsSvc: embeddingsSvc,
+		allowed: models.VerseFilter{
+			Books:      cfg.AllowedBooks,
+			Testaments: cfg.AllowedTestaments,
+		},
+		topicSources:           cfg.TopicSources,
+		topicSourcesByCategory: cfg.TopicSourcesByCategory,
+		maxTopicCards:          cfg.MaxTopicCards,
+		topicQuery:             newQueryParser(cfg.TopicStopWords, cfg.TopicSynonyms, cfg.TopicStemming),
+		popularityWeight:       cfg.PopularityBoostWeight,
+		pericopeWindow:         cfg.PericopeFallbackWindow,
+	}
+}
+
+// scopedFilter narrows a requested filter to the deployment's allowed set
+// Returns false when the two are disjoint and nothing can match
+func (s *VectorSearchService) scopedFilter(requested models.VerseFilter) (models.VerseFilter, bool) {
+	books, ok := intersect(s.allowed.Books, requested.Books)
+	if !ok {
+		return models.VerseFilter{}, false
+	}
+	testaments, ok := intersect(s.allowed.Testaments, requested.Testaments)
+	if !ok {
+		return models.VerseFilter{}, false
 	}
+	return models.VerseFilter{
+		Books:        books,
+		Testaments:   testaments,
+		MinBookOrder: requested.MinBookOrder,
+		MaxBookOrder: requested.MaxBookOrder,
+	}, true
 }
 
-// SearchVerses embeds a query and performs vector search
-func (s *VectorSearchService) SearchVerses(ctx context.Context, query string, topK int) ([]models.ScoredVerse, error) {
+// intersect combines two allow-lists where an empty list means "anything"
+// Returns false when both are non-empty and share no values
+func intersect(allowed, requested []string) ([]string, bool) {
+	if len(allowed) == 0 {
+		return requested, true
+	}
+	if len(requested) == 0 {
+		return allowed, true
+	}
+
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, v := range allowed {
+		allowedSet[v] = true
+	}
+	var result []string
+	for _, v := range requested {
+		if allowedSet[v] {
+			result = append(result, v)
+		}
+	}
+	return result, len(result) > 0
+}
+
+// postFilterOverFetch is how many extra candidates to retrieve per requested
+// result when post-retrieval filters may discard some of them
+const postFilterOverFetch = 3
+
+// EmbedQuery embeds a search query once so callers can fan it out to several searches
+func (s *VectorSearchService) EmbedQuery(ctx context.Context, query string) ([]float64, error) {
+	start := time.Now()
 	embedding, err := s.embeddingsSvc.EmbedQuery(ctx, query)
+	metrics.EmbeddingDuration.WithLabelValues("query", metrics.Outcome(err)).Observe(metrics.Since(start))
+	if err != nil {
+		logging.FromContext(ctx).Error("embedding failed", "task_type", "query", "latency_ms", time.Since(start).Milliseconds(), "error", err)
+	}
+	return embedding, err
+}
+
+// EmbedDocument embeds text the way verses are embedded for the index
+func (s *VectorSearchService) EmbedDocument(ctx context.Context, text string) ([]float64, error) {
+	start := time.Now()
+	embedding, err := s.embeddingsSvc.EmbedVerse(ctx, text)
+	metrics.EmbeddingDuration.WithLabelValues("document", metrics.Outcome(err)).Observe(metrics.Since(start))
+	if err != nil {
+		logging.FromContext(ctx).Error("embedding failed", "task_type", "document", "latency_ms", time.Since(start).Milliseconds(), "error", err)
+	}
+	return embedding, err
+}
+
+// PingVectorBackend verifies the vector search backend can serve queries
+func (s *VectorSearchService) PingVectorBackend(ctx context.Context) error {
+	return s.vectorRepo.Ping(ctx)
+}
+
+// CheckEmbeddings verifies the embedding provider responds with correctly sized
+// embeddings, returning the dimensions received and the dimensions expected
+func (s *VectorSearchService) CheckEmbeddings(ctx context.Context) (int, int, error) {
+	dims, err := s.embeddingsSvc.Check(ctx)
+	return dims, s.embeddingsSvc.Dimensions(), err
+}
+
+// SearchVerses embeds a query and performs vector search within the allowed scope
+func (s *VectorSearchService) SearchVerses(ctx context.Context, query string, topK int, opts models.VerseSearchOptions) ([]models.ScoredVerse, error) {
+	embedding, err := s.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return s.SearchVersesByEmbeddingPrecomputed(ctx, embedding, topK, opts)
+}
+
+// SearchVersesByEmbeddingPrecomputed performs vector search with an already-computed query embedding
+func (s *VectorSearchService) SearchVersesByEmbeddingPrecomputed(ctx context.Context, embedding []float64, topK int, opts models.VerseSearchOptions) ([]models.ScoredVerse, error) {
+	filter, ok := s.scopedFilter(opts.VerseFilter())
+	if !ok {
+		return []models.ScoredVerse{}, nil
+	}
+
+	// Re-ranking and post-filters change which verses land on a page, so those paths
+	// fetch everything up to the page end and slice in-process; otherwise the backend
+	// skips the offset itself
+	reranked := hasPostFilters(opts) || s.popularityWeight > 0 || opts.Diversity > 0
+	search := models.VectorSearchOptions{
+		TopK:              topK,
+		Offset:            opts.Offset,
+		Filter:            filter,
+		IncludeEmbeddings: opts.Diversity > 0,
+		MaxPerBook:        opts.MaxPerBook,
+	}
+	if reranked {
+		search.TopK = (topK + opts.Offset) * postFilterOverFetch
+		search.Offset = 0
+	}
+
+	verses, err := s.searchDeduped(ctx, embedding, search)
 	if err != nil {
 		return nil, err
 	}
-	return s.vectorRepo.SearchVersesByEmbedding(ctx, embedding, topK)
+	verses = aboveMinScore(postRetrieval(verses, len(verses), opts), opts.MinScore)
+	s.applyPopularity(ctx, verses)
+	if opts.Diversity > 0 {
+		verses = maximalMarginalRelevance(verses, topK+opts.Offset, opts.Diversity)
+	}
+	if reranked {
+		return page(verses, opts.Offset, topK), nil
+	}
+	if len(verses) > topK {
+		verses = verses[:topK]
+	}
+	return verses, nil
+}
+
+// maxDedupeRefetches bounds how many times searchDeduped widens a search to back-fill
+// results dropped as duplicates
+const maxDedupeRefetches = 2
+
+// searchDeduped runs search and drops repeated verses, re-fetching with a larger TopK
+// while duplicates left a full page short
+func (s *VectorSearchService) searchDeduped(ctx context.Context, embedding []float64, search models.VectorSearchOptions) ([]models.ScoredVerse, error) {
+	want := search.TopK
+	for attempt := 0; ; attempt++ {
+		verses, err := s.vectorRepo.SearchVersesByEmbedding(ctx, embedding, search)
+		if err != nil {
+			return nil, err
+		}
+		fetched := len(verses)
+		verses = dedupeVerses(verses)
+		if len(verses) >= want || fetched < search.TopK || attempt == maxDedupeRefetches {
+			return verses, nil
+		}
+		search.TopK += want - len(verses)
+	}
+}
+
+// dedupeVerses keeps one result per OSIS id, with its highest score
+// An index holding stale or augmented copies of a verse can return it more than once.
+// Order is left to postRetrieval, which re-sorts by score.
+func dedupeVerses(verses []models.ScoredVerse) []models.ScoredVerse {
+	seen := make(map[string]int, len(verses))
+	results := verses[:0]
+	for _, v := range verses {
+		if i, ok := seen[v.VerseID]; ok {
+			if v.Score > results[i].Score {
+				results[i] = v
+			}
+			continue
+		}
+		seen[v.VerseID] = len(results)
+		results = append(results, v)
+	}
+	return results
+}
+
+// applyPopularity reorders verses by relevance plus a small popularity boost
+// The boost only decides between near-tied scores; reported scores are unchanged.
+// A failed popularity lookup is logged and leaves the relevance order intact.
+func (s *VectorSearchService) applyPopularity(ctx context.Context, verses []models.ScoredVerse) {
+	if s.popularityWeight <= 0 || len(verses) < 2 {
+		return
+	}
+
+	ids := make([]string, len(verses))
+	for i, v := range verses {
+		ids[i] = v.VerseID
+	}
+	popularity, err := s.verseRepo.GetPopularity(ctx, ids)
+	if err != nil {
+		logging.FromContext(ctx).Warn("popularity lookup failed", "error", err)
+		return
+	}
+
+	rank := func(v models.ScoredVerse) float64 {
+		return v.Score + s.popularityWeight*popularity[v.VerseID]
+	}
+	sort.SliceStable(verses, func(i, j int) bool {
+		return rank(verses[i]) > rank(verses[j])
+	})
+}
+
+// hasPostFilters reports whether any option may discard retrieved verses
+func hasPostFilters(opts models.VerseSearchOptions) bool {
+	return opts.MinTextLength > 0 || opts.MaxTextLength > 0
+}
+
+// postRetrieval applies the shared filters to backend results and trims to topK
+// Equal-scored verses are first put in canonical order so results are reproducible
+func postRetrieval(verses []models.ScoredVerse, topK int, opts models.VerseSearchOptions) []models.ScoredVerse {
+	sortCanonicalTies(verses)
+
+	results := verses[:0]
+	for _, v := range verses {
+		length := utf8.RuneCountInString(v.Text)
+		if opts.MinTextLength > 0 && length < opts.MinTextLength {
+			continue
+		}
+		if opts.MaxTextLength > 0 && length > opts.MaxTextLength {
+			continue
+		}
+		results = append(results, v)
+	}
+
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	return results
+}
+
+// aboveMinScore drops verses whose similarity is below minScore
+// Both backends normalize scores to [0, 1] similarity (repository.NormalizeScore), so
+// the threshold means the same thing whichever one produced them
+func aboveMinScore(verses []models.ScoredVerse, minScore float64) []models.ScoredVerse {
+	if minScore <= 0 {
+		return verses
+	}
+	results := verses[:0]
+	for _, v := range verses {
+		if v.Score >= minScore {
+			results = append(results, v)
+		}
+	}
+	return results
+}
+
+// sortCanonicalTies orders verses by score, breaking exact ties by book order, chapter
+// and verse. Verses with different scores never change relative order.
+func sortCanonicalTies(verses []models.ScoredVerse) {
+	sort.SliceStable(verses, func(i, j int) bool {
+		a, b := verses[i], verses[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.BookOrder != b.BookOrder {
+			return a.BookOrder < b.BookOrder
+		}
+		if a.Chapter != b.Chapter {
+			return a.Chapter < b.Chapter
+		}
+		return a.Verse < b.Verse
+	})
 }
 
 // SearchVersesCitations performs vector search and returns as citations
-func (s *VectorSearchService) SearchVersesCitations(ctx context.Context, query string, topK int) ([]models.Citation, error) {
-	scoredVerses, err := s.SearchVerses(ctx, query, topK)
+func (s *VectorSearchService) SearchVersesCitations(ctx context.Context, query string, topK int, opts models.VerseSearchOptions) ([]models.Citation, error) {
+	embedding, err := s.EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, err
 	}
+	return s.SearchVersesCitationsPrecomputed(ctx, embedding, topK, opts)
+}
+
+// SearchVersesCitationsPrecomputed performs vector search with an already-computed
+// query embedding and returns as citations
+func (s *VectorSearchService) SearchVersesCitationsPrecomputed(ctx context.Context, embedding []float64, topK int, opts models.VerseSearchOptions) ([]models.Citation, error) {
+	scoredVerses, err := s.SearchVersesByEmbeddingPrecomputed(ctx, embedding, topK, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.toCitations(ctx, scoredVerses, opts)
+}
+
+// toCitations converts ranked verses to citations, applying any requested expansion
+func (s *VectorSearchService) toCitations(ctx context.Context, scoredVerses []models.ScoredVerse, opts models.VerseSearchOptions) ([]models.Citation, error) {
+	citations := citationsFromScored(scoredVerses)
+	if opts.Explain {
+		explainScored(citations, scoredVerses)
+	}
+	return s.finishCitations(ctx, citations, opts)
+}
+
+// explainScored attaches each verse's raw distance and normalized similarity to its
+// citation, or its text rank when it came from full-text search
+func explainScored(citations []models.Citation, scoredVerses []models.ScoredVerse) {
+	for i, v := range scoredVerses {
+		score := v.Score
+		if v.DistanceMeasure == "" {
+			citations[i].Explain = &models.ScoreExplanation{TextRank: &score}
+			continue
+		}
+		distance := v.Distance
+		citations[i].Explain = &models.ScoreExplanation{
+			DistanceMeasure: v.DistanceMeasure,
+			Distance:        &distance,
+			Similarity:      &score,
+		}
+	}
+}
 
+// citationsFromScored converts ranked verses to citations carrying their relevance score
+func citationsFromScored(scoredVerses []models.ScoredVerse) []models.Citation {
 	citations := make([]models.Citation, len(scoredVerses))
 	for i, v := range scoredVerses {
 		score := v.Score
@@ -57,17 +385,198 @@ func (s *VectorSearchService) SearchVersesCitations(ctx context.Context, query s
 			RelevanceScore: &score,
 		}
 	}
+	return citations
+}
+
+// finishCitations applies grouping and expansion to a final ranked citation list
+func (s *VectorSearchService) finishCitations(ctx context.Context, citations []models.Citation, opts models.VerseSearchOptions) ([]models.Citation, error) {
+	if opts.GroupAdjacent {
+		citations = groupAdjacent(citations)
+	}
+	return s.expand(ctx, citations, opts)
+}
+
+// groupAdjacent collapses runs of consecutive verses in the same chapter into a single
+// passage citation. Input must be score-ordered; each passage takes the position and
+// score of its best member, and its text joins the members in verse order.
+func groupAdjacent(citations []models.Citation) []models.Citation {
+	type chapterVerse struct {
+		book           string
+		chapter, verse int
+	}
+	index := make(map[chapterVerse]int, len(citations))
+	for i, c := range citations {
+		index[chapterVerse{c.Book, c.Chapter, c.Verse}] = i
+	}
+
+	used := make([]bool, len(citations))
+	grouped := make([]models.Citation, 0, len(citations))
+	for i, c := range citations {
+		if used[i] {
+			continue
+		}
+		used[i] = true
+
+		start, end := c.Verse, c.Verse
+		for {
+			j, ok := index[chapterVerse{c.Book, c.Chapter, start - 1}]
+			if !ok || used[j] {
+				break
+			}
+			used[j] = true
+			start--
+		}
+		for {
+			j, ok := index[chapterVerse{c.Book, c.Chapter, end + 1}]
+			if !ok || used[j] {
+				break
+			}
+			used[j] = true
+			end++
+		}
+		if start == end {
+			grouped = append(grouped, c)
+			continue
+		}
+
+		texts := make([]string, 0, end-start+1)
+		for v := start; v <= end; v++ {
+			texts = append(texts, citations[index[chapterVerse{c.Book, c.Chapter, v}]].Text)
+		}
+		first := citations[index[chapterVerse{c.Book, c.Chapter, start}]]
+		grouped = append(grouped, models.Citation{
+			VerseID:        first.VerseID,
+			Text:           strings.Join(texts, " "),
+			Book:           c.Book,
+			Chapter:        c.Chapter,
+			Verse:          start,
+			EndVerse:       end,
+			RelevanceScore: c.RelevanceScore,
+			Explain:        c.Explain,
+		})
+	}
+	return grouped
+}
+
+// expand applies the requested citation expansions in place
+func (s *VectorSearchService) expand(ctx context.Context, citations []models.Citation, opts models.VerseSearchOptions) ([]models.Citation, error) {
+	if opts.Expand == models.ExpandPericope {
+		if err := s.expandPericopes(ctx, citations); err != nil {
+			return nil, err
+		}
+	}
+	if opts.ContextWindow > 0 {
+		if err := s.attachContext(ctx, citations, min(opts.ContextWindow, models.MaxContextWindow)); err != nil {
+			return nil, err
+		}
+	}
+	if opts.IncludeCrossRefs {
+		if err := s.attachCrossRefs(ctx, citations); err != nil {
+			return nil, err
+		}
+	}
 	return citations, nil
 }
 
+// attachCrossRefs sets each citation's cross-references using a single batched lookup
+func (s *VectorSearchService) attachCrossRefs(ctx context.Context, citations []models.Citation) error {
+	filter, ok := s.scopedFilter(models.VerseFilter{})
+	if !ok || len(citations) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(citations))
+	for i, c := range citations {
+		ids[i] = c.VerseID
+	}
+	refs, err := s.verseRepo.GetCrossReferences(ctx, ids, models.CrossRefsPerResult, filter)
+	if err != nil {
+		return err
+	}
+
+	for i := range citations {
+		citations[i].CrossRefs = refs[citations[i].VerseID]
+	}
+	return nil
+}
+
+// attachContext sets each citation's surrounding verses within its chapter
+func (s *VectorSearchService) attachContext(ctx context.Context, citations []models.Citation, window int) error {
+	if len(citations) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(citations))
+	for i, c := range citations {
+		ids[i] = c.VerseID
+	}
+	windows, err := s.verseRepo.GetVerseWindows(ctx, ids, window)
+	if err != nil {
+		return err
+	}
+
+	for i := range citations {
+		citations[i].Context = windows[citations[i].VerseID]
+	}
+	return nil
+}
+
+// expandPericopes attaches the pericope containing each citation
+// Verses without pericope data get a ±pericopeWindow chapter window flagged as a fallback
+func (s *VectorSearchService) expandPericopes(ctx context.Context, citations []models.Citation) error {
+	if len(citations) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(citations))
+	for i, c := range citations {
+		ids[i] = c.VerseID
+	}
+	pericopes, err := s.verseRepo.GetPericopes(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, id := range ids {
+		if pericopes[id] == nil {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		windows, err := s.verseRepo.GetVerseWindows(ctx, missing, s.pericopeWindow)
+		if err != nil {
+			return err
+		}
+		for id, verses := range windows {
+			pericopes[id] = &models.Pericope{
+				StartVerseID: verses[0].VerseID,
+				EndVerseID:   verses[len(verses)-1].VerseID,
+				Verses:       verses,
+				Fallback:     true,
+			}
+		}
+	}
+
+	for i := range citations {
+		citations[i].Pericope = pericopes[citations[i].VerseID]
+	}
+	return nil
+}
+
 // SearchTopics searches topics by keywords
-func (s *VectorSearchService) SearchTopics(ctx context.Context, query string, topK int) ([]models.ScoredTopic, error) {
-	words := tokenizeWords(query)
-	if len(words) == 0 {
+// Terms may be boosted with "term^weight" syntax, e.g. "grace^2 faith"
+func (s *VectorSearchService) SearchTopics(ctx context.Context, query string, topK int, opts models.TopicSearchOptions) ([]models.ScoredTopic, error) {
+	terms := s.topicQuery.terms(query)
+	if len(terms) == 0 {
 		return []models.ScoredTopic{}, nil
 	}
 
-	results, err := s.topicRepo.SearchByWords(ctx, words, topK)
+	fetchK := topK
+	if !opts.AllSources {
+		fetchK *= topicDedupOverFetch
+	}
+	results, err := s.topicRepo.SearchByWords(ctx, terms, fetchK)
 	if err != nil {
 		return nil, err
 	}
@@ -75,78 +584,440 @@ func (s *VectorSearchService) SearchTopics(ctx context.Context, query string, to
 	topics := make([]models.ScoredTopic, len(results))
 	for i, r := range results {
 		topics[i] = models.ScoredTopic{
-			TopicID:     r.Topic.TopicID,
-			Name:        r.Topic.Name,
-			Source:      r.Topic.Source,
-			Category:    r.Category,
-			ChapterRefs: r.Topic.ChapterRefs,
-			VerseCount:  r.VerseCount,
-			Score:       r.Score,
+			TopicID:      r.Topic.TopicID,
+			Name:         r.Topic.Name,
+			Source:       r.Topic.Source,
+			Category:     r.Category,
+			ChapterRefs:  r.Topic.ChapterRefs,
+			VerseCount:   r.VerseCount,
+			Score:        r.Score,
+			MatchedWords: sourceWords(r.MatchedWords, terms),
+		}
+	}
+	if !opts.AllSources {
+		topics = s.dedupeTopics(topics)
+	}
+	topics = page(topics, 0, topK)
+
+	if err := s.attachChapterRefs(ctx, topics); err != nil {
+		return nil, err
+	}
+	if opts.IncludeTopVerse {
+		if err := s.attachTopVerses(ctx, topics); err != nil {
+			return nil, err
 		}
 	}
 	return topics, nil
 }
 
-// preferredSources defines source priority for topic cards (higher index = lower priority)
-var preferredSources = []string{
-	"claude_4.5_opus",
-	"torreys_topical_textbook",
-	"naves_topical_bible",
+// topicDedupOverFetch over-fetches topic matches so collapsing same-named topics from
+// different sources still fills the requested limit (one per source at most)
+const topicDedupOverFetch = 3
+
+// dedupeTopics collapses topics with the same normalized name into one, keeping the
+// most preferred source for the category (as topic cards do) at the position and score
+// of the group's best match. Other sources are listed in MergedSources.
+func (s *VectorSearchService) dedupeTopics(topics []models.ScoredTopic) []models.ScoredTopic {
+	groups := make(map[string][]models.ScoredTopic)
+	var order []string
+	for _, t := range topics {
+		key := strings.Join(splitWords(t.Name), " ")
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], t)
+	}
+
+	deduped := make([]models.ScoredTopic, 0, len(order))
+	for _, key := range order {
+		group := groups[key]
+		kept := group[0]
+		for _, preferred := range s.preferredSources(group[0].Category) {
+			if i := slices.IndexFunc(group, func(t models.ScoredTopic) bool { return t.Source == preferred }); i >= 0 {
+				kept = group[i]
+				break
+			}
+		}
+		kept.Score = group[0].Score
+		for _, t := range group {
+			if t.TopicID != kept.TopicID && !slices.Contains(kept.MergedSources, t.Source) {
+				kept.MergedSources = append(kept.MergedSources, t.Source)
+			}
+		}
+		deduped = append(deduped, kept)
+	}
+	return deduped
+}
+
+// chapterRefsPerTopic caps the chapter labels attached to each topic
+const chapterRefsPerTopic = 5
+
+// attachChapterRefs sets each topic's most represented chapters using a single batched lookup
+func (s *VectorSearchService) attachChapterRefs(ctx context.Context, topics []models.ScoredTopic) error {
+	filter, ok := s.scopedFilter(models.VerseFilter{})
+	if !ok || len(topics) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(topics))
+	for i, t := range topics {
+		ids[i] = t.TopicID
+	}
+	refs, err := s.topicRepo.GetChapterRefs(ctx, ids, chapterRefsPerTopic, filter)
+	if err != nil {
+		return err
+	}
+
+	for i := range topics {
+		topics[i].ChapterRefs = refs[topics[i].TopicID]
+	}
+	return nil
+}
+
+// attachTopVerses sets each topic's preview verse using a single batched lookup
+func (s *VectorSearchService) attachTopVerses(ctx context.Context, topics []models.ScoredTopic) error {
+	filter, ok := s.scopedFilter(models.VerseFilter{})
+	if !ok || len(topics) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(topics))
+	for i, t := range topics {
+		ids[i] = t.TopicID
+	}
+	topVerses, err := s.topicRepo.GetTopVerses(ctx, ids, filter)
+	if err != nil {
+		return err
+	}
+
+	for i := range topics {
+		if v, ok := topVerses[topics[i].TopicID]; ok {
+			topics[i].TopVerse = &v
+		}
+	}
+	return nil
+}
+
+// preferredSources returns the source priority for topic cards in a category
+// (higher index = lower priority), falling back to the global default ordering
+func (s *VectorSearchService) preferredSources(category string) []string {
+	if sources, ok := s.topicSourcesByCategory[category]; ok && len(sources) > 0 {
+		return sources
+	}
+	return s.topicSources
 }
 
 // GetTopicCard returns a TopicCard for the best matching topic if score is high enough
-// Prefers Claude-curated topics over other sources when available
-func (s *VectorSearchService) GetTopicCard(ctx context.Context, topics []models.ScoredTopic, minScore float64, verseLimit int) (*models.TopicCard, error) {
-	if len(topics) == 0 {
+// Source preference follows the category of the strongest match (e.g. Claude-curated
+// for concepts, Nave's for people and places)
+func (s *VectorSearchService) GetTopicCard(ctx context.Context, topics []models.ScoredTopic, minScore float64, verseOpts models.TopicVerseOptions) (*models.TopicCard, error) {
+	selectedTopic := s.selectTopic(topics, minScore)
+	if selectedTopic == nil {
 		return nil, nil
 	}
+	return s.buildTopicCard(ctx, selectedTopic, verseOpts)
+}
+
+// GetTopicCards returns up to maxCards topic cards, one per distinct concept in the query
+// Topics are grouped into concepts by which query words they match, so "grace and the
+// second coming" yields one Grace card and one Second Coming card rather than several
+// near-synonym Grace cards. Preferred-source selection is applied within each concept.
+func (s *VectorSearchService) GetTopicCards(ctx context.Context, query string, topics []models.ScoredTopic, minScore float64, verseOpts models.TopicVerseOptions, maxCards int) ([]models.TopicCard, error) {
+	var words []string
+	for _, term := range s.topicQuery.terms(query) {
+		words = append(words, term.Word)
+	}
+
+	cards := []models.TopicCard{}
+	for _, concept := range groupTopicsByConcept(topics, words, minScore) {
+		if len(cards) >= maxCards {
+			break
+		}
+		selectedTopic := s.selectTopic(concept, minScore)
+		if selectedTopic == nil {
+			continue
+		}
+		card, err := s.buildTopicCard(ctx, selectedTopic, verseOpts)
+		if err != nil {
+			return nil, err
+		}
+		if card != nil {
+			cards = append(cards, *card)
+		}
+	}
+	return cards, nil
+}
 
-	// Find the best topic: prefer Claude source, then by score
-	var selectedTopic *models.ScoredTopic
+// selectTopic picks the card topic from score-ordered topics: the first topic from the
+// most preferred source for the top match's category, else the top match itself
+func (s *VectorSearchService) selectTopic(topics []models.ScoredTopic, minScore float64) *models.ScoredTopic {
+	if len(topics) == 0 || topics[0].Score < minScore {
+		return nil
+	}
 
 	// First pass: look for preferred sources in order
-	for _, preferredSource := range preferredSources {
+	for _, preferredSource := range s.preferredSources(topics[0].Category) {
 		for i := range topics {
 			if topics[i].Source == preferredSource && topics[i].Score >= minScore {
-				selectedTopic = &topics[i]
+				return &topics[i]
+			}
+		}
+	}
+
+	// Fallback: use highest scoring topic if no preferred source found
+	return &topics[0]
+}
+
+// buildTopicCard fetches a topic's verses, keeping only those within the allowed scope
+func (s *VectorSearchService) buildTopicCard(ctx context.Context, topic *models.ScoredTopic, verseOpts models.TopicVerseOptions) (*models.TopicCard, error) {
+	filter, ok := s.scopedFilter(verseOpts.Filter)
+	if !ok {
+		return nil, nil
+	}
+	verseOpts.Filter = filter
+	verses, err := s.topicRepo.GetTopicVerses(ctx, topic.TopicID, verseOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TopicCard{
+		TopicID:     topic.TopicID,
+		Name:        topic.Name,
+		Category:    topic.Category,
+		Source:      topic.Source,
+		VerseCount:  topic.VerseCount,
+		Score:       topic.Score,
+		ChapterRefs: topic.ChapterRefs,
+		TopVerses:   verses,
+	}, nil
+}
+
+// groupTopicsByConcept partitions score-ordered topics into concepts
+// A topic joins the first concept whose matched query words overlap its own; topics
+// matching no query word by name form their own concept. Groups keep score order,
+// and only topics scoring at least minScore are considered.
+func groupTopicsByConcept(topics []models.ScoredTopic, words []string, minScore float64) [][]models.ScoredTopic {
+	type concept struct {
+		words  map[string]bool
+		topics []models.ScoredTopic
+	}
+	var concepts []*concept
+
+	for _, topic := range topics {
+		if topic.Score < minScore {
+			continue
+		}
+
+		name := foldText(topic.Name) // Query words are folded the same way
+		matched := make(map[string]bool)
+		for _, w := range words {
+			if strings.Contains(name, w) {
+				matched[w] = true
+			}
+		}
+
+		var home *concept
+		for _, c := range concepts {
+			for w := range matched {
+				if c.words[w] {
+					home = c
+					break
+				}
+			}
+			if home != nil {
 				break
 			}
 		}
-		if selectedTopic != nil {
-			break
+		if home == nil {
+			home = &concept{words: make(map[string]bool)}
+			concepts = append(concepts, home)
+		}
+		for w := range matched {
+			home.words[w] = true
 		}
+		home.topics = append(home.topics, topic)
 	}
 
-	// Fallback: use highest scoring topic if no preferred source found
-	if selectedTopic == nil {
-		if topics[0].Score >= minScore {
-			selectedTopic = &topics[0]
+	groups := make([][]models.ScoredTopic, len(concepts))
+	for i, c := range concepts {
+		groups[i] = c.topics
+	}
+	return groups
+}
+
+// CheckEmbeddingConsistency re-embeds a stored verse as a document and compares it to
+// the embedding stored at ingestion time, returning their cosine similarity.
+// A low similarity means the query-time embedder (model, instruction prefix, or text
+// template) has drifted from the one used to build the corpus, or that the index
+// mixes vectors built with different templates, which silently
+// degrades every search. Returns an error when similarity is below minSimilarity.
+// An enriched verse may be stored with its annotations as themes (enrichment apply
+// -overwrite-embedding) or bare, so both renderings are embedded and the closer counts.
+func (s *VectorSearchService) CheckEmbeddingConsistency(ctx context.Context, verseID string, minSimilarity float64) (float64, error) {
+	text, stored, err := s.verseRepo.GetVerseEmbedding(ctx, verseID)
+	if err != nil {
+		return 0, fmt.Errorf("load stored embedding for %s: %w", verseID, err)
+	}
+	annotations, err := s.verseRepo.GetAnnotations(ctx, verseID)
+	if err != nil {
+		return 0, fmt.Errorf("load annotations for %s: %w", verseID, err)
+	}
+
+	documents, err := consistencyDocuments(text, annotations)
+	if err != nil {
+		return 0, err
+	}
+	fresh, err := s.embeddingsSvc.EmbedVerses(ctx, documents)
+	if err != nil {
+		return 0, fmt.Errorf("embed %s: %w", verseID, err)
+	}
+
+	similarity := -1.0
+	for _, embedding := range fresh {
+		if len(embedding) != len(stored) {
+			return 0, fmt.Errorf("embedding dimension mismatch for %s: stored %d, embedder returned %d", verseID, len(stored), len(embedding))
 		}
+		similarity = max(similarity, cosineSimilarity(embedding, stored))
+	}
+	if similarity < minSimilarity {
+		return similarity, fmt.Errorf("embedding drift for %s: similarity %.4f below %.4f (check embedder model and instruction match ingestion)", verseID, similarity, minSimilarity)
 	}
+	return similarity, nil
+}
 
-	if selectedTopic == nil {
-		return nil, nil
+// consistencyDocuments renders a verse the ways it may have been embedded: the bare
+// text, plus the text with its annotations as themes when it has any
+func consistencyDocuments(text string, annotations []models.VerseAnnotation) ([]string, error) {
+	bare, err := pkgservices.FormatEmbeddingText(text, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(annotations) == 0 {
+		return []string{bare}, nil
 	}
 
-	// Fetch verses for this topic
-	verses, err := s.topicRepo.GetTopicVerses(ctx, selectedTopic.TopicID, verseLimit)
+	themes := make([]string, len(annotations))
+	for i, a := range annotations {
+		themes[i] = a.Annotation
+	}
+	enriched, err := pkgservices.FormatEmbeddingText(text, themes)
 	if err != nil {
 		return nil, err
 	}
+	return []string{bare, enriched}, nil
+}
 
-	return &models.TopicCard{
-		TopicID:    selectedTopic.TopicID,
-		Name:       selectedTopic.Name,
-		Category:   selectedTopic.Category,
-		Source:     selectedTopic.Source,
-		VerseCount: selectedTopic.VerseCount,
-		Score:      selectedTopic.Score,
-		TopVerses:  verses,
+// ListTopics returns a page of the topic directory
+func (s *VectorSearchService) ListTopics(ctx context.Context, opts models.TopicListOptions) (*models.TopicListResponse, error) {
+	topics, total, err := s.topicRepo.ListTopics(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TopicListResponse{
+		Topics: topics,
+		Total:  total,
+		Limit:  opts.Limit,
+		Offset: opts.Offset,
 	}, nil
 }
 
-// stopWords contains common words to exclude from search
-var stopWords = map[string]bool{
+// warmupQuery is the throwaway query embedded and searched by Warmup
+const warmupQuery = "grace and peace"
+
+// Warmup embeds a throwaway query and runs one vector search with it, establishing the
+// embedder and vector backend connections before the first real request
+func (s *VectorSearchService) Warmup(ctx context.Context) error {
+	embedding, err := s.EmbedQuery(ctx, warmupQuery)
+	if err != nil {
+		return fmt.Errorf("embed warm-up query: %w", err)
+	}
+	if _, err := s.SearchVersesByEmbeddingPrecomputed(ctx, embedding, 1, models.VerseSearchOptions{}); err != nil {
+		return fmt.Errorf("warm-up search: %w", err)
+	}
+	return nil
+}
+
+// GetTopic returns a topic with a page of its in-scope verses, most important first
+// opts.MaxTier limits the verses to tiers 1..MaxTier (0 = all tiers) and book, an OSIS
+// id or name, to one book (empty = all). Returns ErrInvalidReference for unknown books
+// and repository.ErrNotFound for unknown topics.
+func (s *VectorSearchService) GetTopic(ctx context.Context, topicID, book string, opts models.TopicVerseOptions) (*models.TopicDetail, error) {
+	requested := opts.Filter
+	if book != "" {
+		b, err := osis.LookupBook(book)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidReference, err)
+		}
+		requested.Books = []string{b.ID}
+	}
+
+	topic, err := s.topicRepo.GetTopic(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+	topic.Limit, topic.Offset = opts.Limit, opts.Offset
+
+	topic.Verses = []models.Citation{}
+	if filter, ok := s.scopedFilter(requested); ok {
+		// Ask for one extra verse to learn whether another page exists
+		opts.Filter = filter
+		opts.Limit++
+		topic.Verses, err = s.topicRepo.GetTopicVerses(ctx, topicID, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(topic.Verses) > topic.Limit {
+			topic.Verses = topic.Verses[:topic.Limit]
+			topic.HasMore = true
+		}
+	}
+	return topic, nil
+}
+
+// SearchTopicVerses semantically ranks only the verses mapped to a topic, combining
+// its curated scope with query similarity
+func (s *VectorSearchService) SearchTopicVerses(ctx context.Context, topicID string, req models.TopicVerseSearchRequest) ([]models.Citation, error) {
+	filter, ok := s.scopedFilter(models.VerseFilter{})
+	if !ok {
+		return []models.Citation{}, nil
+	}
+	embedding, err := s.EmbedQuery(ctx, req.Query)
+	if err != nil {
+		return nil, err
+	}
+	verses, err := s.topicRepo.SearchTopicVerses(ctx, topicID, embedding, models.TopicVerseOptions{
+		Limit:   req.Limit,
+		MaxTier: req.MaxTier,
+		Filter:  filter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return citationsFromScored(aboveMinScore(verses, req.MinScore)), nil
+}
+
+// GetRelatedTopics returns a topic's parent, siblings and children
+func (s *VectorSearchService) GetRelatedTopics(ctx context.Context, topicID string) (*models.RelatedTopics, error) {
+	return s.topicRepo.GetRelatedTopics(ctx, topicID)
+}
+
+// GetTopicCoverage returns per-tier mapped and canonical verse counts for a topic
+func (s *VectorSearchService) GetTopicCoverage(ctx context.Context, topicID string) (*models.TopicCoverage, error) {
+	return s.topicRepo.GetTopicCoverage(ctx, topicID)
+}
+
+// StopWords returns the effective topic query stop words, sorted
+func (s *VectorSearchService) StopWords() []string {
+	words := make([]string, 0, len(s.topicQuery.stopWords))
+	for word := range s.topicQuery.stopWords {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
+
+// defaultStopWords contains common words to exclude from search
+var defaultStopWords = map[string]bool{
 	"the": true, "and": true, "for": true, "that": true, "with": true,
 	"this": true, "are": true, "but": true, "not": true, "you": true,
 	"all": true, "was": true, "his": true, "her": true, "from": true,
@@ -156,11 +1027,208 @@ var stopWords = map[string]bool{
 	"when": true, "then": true, "than": true, "into": true, "upon": true,
 }
 
-// tokenizeWords splits query into searchable words
-func tokenizeWords(query string) []string {
-	words := strings.FieldsFunc(strings.ToLower(query), func(c rune) bool {
+// mergeStopWords returns defaultStopWords plus extra, lowercased
+func mergeStopWords(extra []string) map[string]bool {
+	words := make(map[string]bool, len(defaultStopWords)+len(extra))
+	for word := range defaultStopWords {
+		words[word] = true
+	}
+	for _, word := range extra {
+		words[strings.ToLower(word)] = true
+	}
+	return words
+}
+
+// defaultTopicSynonyms routes common aliases to the wording of curated topics
+// Keys match whole words of the query; TOPIC_SYNONYMS adds to or replaces entries
+var defaultTopicSynonyms = map[string][]string{
+	"end times":        {"second coming", "last days", "eschatology"},
+	"end of the world": {"second coming", "last days"},
+	"rapture":          {"second coming"},
+	"return of christ": {"second coming"},
+	"hell":             {"lake of fire", "hades", "gehenna", "eternal punishment"},
+	"lake of fire":     {"hell"},
+	"born again":       {"regeneration", "new birth"},
+	"new birth":        {"regeneration"},
+	"holy ghost":       {"holy spirit"},
+	"communion":        {"lord's supper"},
+	"eucharist":        {"lord's supper"},
+	"devil":            {"satan"},
+	"satan":            {"devil"},
+	"afterlife":        {"eternal life", "resurrection", "heaven"},
+	"godhead":          {"trinity"},
+	"tithe":            {"giving", "offering"},
+}
+
+// synonymWeight scales synonym terms so a literal match of the query outranks an
+// equally good match of one of its synonyms
+const synonymWeight = 0.9
+
+// queryParser tokenizes topic queries into weighted terms
+type queryParser struct {
+	stopWords   map[string]bool     // defaultStopWords plus TOPIC_STOP_WORDS
+	synonyms    map[string][]string // Normalized phrase -> alternative phrases
+	synonymKeys []string            // Sorted synonyms keys, for a deterministic term order
+	stem        bool                // Expand words with their stemVariants
+}
+
+// newQueryParser merges the configured stop words and synonyms over the defaults
+func newQueryParser(extraStopWords []string, synonyms map[string][]string, stem bool) queryParser {
+	p := queryParser{
+		stopWords: mergeStopWords(extraStopWords),
+		synonyms:  make(map[string][]string, len(defaultTopicSynonyms)+len(synonyms)),
+		stem:      stem,
+	}
+	for _, m := range []map[string][]string{defaultTopicSynonyms, synonyms} {
+		for phrase, alternatives := range m {
+			p.synonyms[strings.Join(splitWords(phrase), " ")] = alternatives
+		}
+	}
+	for phrase := range p.synonyms {
+		p.synonymKeys = append(p.synonymKeys, phrase)
+	}
+	sort.Strings(p.synonymKeys)
+	return p
+}
+
+// maxTermWeight caps "term^weight" boosts so one term can't swamp all others
+const maxTermWeight = 10.0
+
+// terms tokenizes a query into weighted terms
+// A whitespace-separated field ending in "^n" applies weight n to every word in it;
+// unweighted words (and invalid weights) default to 1. Duplicate words keep the highest weight.
+// Stop words are dropped; with stemming, each remaining word is followed by its
+// stemVariants at the same weight. Synonyms of words or phrases in the query follow
+// at synonymWeight. Expanded terms record the query text they came from in Source.
+func (p queryParser) terms(query string) []models.QueryTerm {
+	var terms []models.QueryTerm
+	index := make(map[string]int)
+	add := func(word string, weight float64, source string) {
+		if i, seen := index[word]; seen {
+			terms[i].Weight = max(terms[i].Weight, weight)
+			return
+		}
+		index[word] = len(terms)
+		terms = append(terms, models.QueryTerm{Word: word, Weight: weight, Source: source})
+	}
+
+	var allWords []string
+	for _, field := range strings.Fields(query) {
+		weight := 1.0
+		if caret := strings.LastIndex(field, "^"); caret >= 0 {
+			if w, err := strconv.ParseFloat(field[caret+1:], 64); err == nil && w > 0 {
+				weight = min(w, maxTermWeight)
+			}
+			field = field[:caret]
+		}
+		allWords = append(allWords, splitWords(field)...)
+
+		for _, word := range tokenizeWords(field, p.stopWords) {
+			add(word, weight, "")
+			if p.stem {
+				for _, variant := range stemVariants(word) {
+					add(variant, weight, word)
+				}
+			}
+		}
+	}
+
+	// Synonym phrases may span fields and include stop words ("end of the world")
+	phrase := " " + strings.Join(allWords, " ") + " "
+	for _, key := range p.synonymKeys {
+		if !strings.Contains(phrase, " "+key+" ") {
+			continue
+		}
+		for _, alternative := range p.synonyms[key] {
+			add(strings.ToLower(alternative), synonymWeight, key)
+		}
+	}
+	return terms
+}
+
+// sourceWords maps matched term words back to the query text they were expanded from,
+// so highlighting uses the user's words ("forgiving", not the stem "forgiv")
+func sourceWords(matched []string, terms []models.QueryTerm) []string {
+	sources := make(map[string]string, len(terms))
+	for _, t := range terms {
+		if t.Source != "" {
+			sources[t.Word] = t.Source
+		}
+	}
+
+	words := make([]string, 0, len(matched))
+	seen := make(map[string]bool, len(matched))
+	for _, word := range matched {
+		if source, ok := sources[word]; ok {
+			word = source
+		}
+		if !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// minStemLength keeps short stems (e.g. "lie" from "lies") from matching inside
+// unrelated topic names ("Believe")
+const minStemLength = 4
+
+// stemFamilies links Snowball stems of words that belong to one topic but do not share
+// a stem: irregular verbs and Latinate nouns, e.g. "saved" should find Salvation
+var stemFamilies = map[string][]string{
+	"forgav":   {"forgiv"},
+	"save":     {"salvat", "savior", "saviour"},
+	"redeem":   {"redempt"},
+	"baptiz":   {"baptism"},
+	"wept":     {"weep"},
+	"children": {"child"},
+	"believ":   {"belief"},
+	"chose":    {"choos"},
+}
+
+// stemVariants returns the Snowball stem of a word plus its stem family, omitting the
+// word itself and stems shorter than minStemLength
+// Topics match by substring, so "forgiv" finds Forgiveness from "forgiving" or "forgiven"
+func stemVariants(word string) []string {
+	stem := english.Stem(word, false)
+	var variants []string
+	if stem != word && len(stem) >= minStemLength {
+		variants = append(variants, stem)
+	}
+	for _, related := range stemFamilies[stem] {
+		if related != word {
+			variants = append(variants, related)
+		}
+	}
+	return variants
+}
+
+// splitWords lowercases and folds text (see foldText) and splits it into ASCII
+// alphanumeric words; curly quotes and other punctuation separate words
+func splitWords(text string) []string {
+	return strings.FieldsFunc(foldText(text), func(c rune) bool {
 		return !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9'))
 	})
+}
+
+// foldText lowercases text, decomposing it (NFKD) and dropping combining marks, so
+// "Resurrección" folds to "resurreccion" and compatibility forms such as ligatures or
+// full-width letters become their plain letters. ASCII text is only lowercased.
+func foldText(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range norm.NFKD.String(text) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// tokenizeWords splits query into searchable words, dropping stopWords
+func tokenizeWords(query string, stopWords map[string]bool) []string {
+	words := splitWords(query)
 
 	filtered := make([]string, 0, len(words))
 	for _, word := range words {
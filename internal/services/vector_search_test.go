@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sola-scriptura-search-api/internal/models"
+	"github.com/sola-scriptura-search-api/internal/repository"
+)
+
+// stubVectorRepo returns the first TopK of its verses, recording each requested TopK
+type stubVectorRepo struct {
+	verses   []models.ScoredVerse
+	requests []int
+}
+
+func (r *stubVectorRepo) SearchVersesByEmbedding(_ context.Context, _ []float64, opts models.VectorSearchOptions) ([]models.ScoredVerse, error) {
+	r.requests = append(r.requests, opts.TopK)
+	verses := append([]models.ScoredVerse(nil), r.verses[:min(opts.TopK, len(r.verses))]...)
+	return verses, nil
+}
+
+func (r *stubVectorRepo) Ping(context.Context) error { return nil }
+
+func scored(id string, score float64) models.ScoredVerse {
+	return models.ScoredVerse{VerseID: id, Score: score}
+}
+
+func TestSearchVersesDedupesAndBackfills(t *testing.T) {
+	repo := &stubVectorRepo{verses: []models.ScoredVerse{
+		scored("John.3.16", 0.9),
+		scored("Rom.5.8", 0.8),
+		scored("John.3.16", 0.7), // augmented copy, scoring lower
+		scored("Eph.2.8", 0.6),
+		scored("1John.4.9", 0.5),
+	}}
+	svc := &VectorSearchService{vectorRepo: repo}
+
+	verses, err := svc.SearchVersesByEmbeddingPrecomputed(context.Background(), []float64{1}, 3, models.VerseSearchOptions{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+
+	want := []models.ScoredVerse{scored("John.3.16", 0.9), scored("Rom.5.8", 0.8), scored("Eph.2.8", 0.6)}
+	if len(verses) != len(want) {
+		t.Fatalf("got %d verses %v, want %d", len(verses), verses, len(want))
+	}
+	for i, v := range verses {
+		if v.VerseID != want[i].VerseID || v.Score != want[i].Score {
+			t.Errorf("verse %d = %s (%.1f), want %s (%.1f)", i, v.VerseID, v.Score, want[i].VerseID, want[i].Score)
+		}
+	}
+	if len(repo.requests) != 2 || repo.requests[1] != 4 {
+		t.Errorf("requested TopK %v, want [3 4]", repo.requests)
+	}
+}
+
+func TestDedupeVersesKeepsHighestScore(t *testing.T) {
+	verses := dedupeVerses([]models.ScoredVerse{
+		scored("Ps.23.1", 0.4),
+		scored("Ps.23.1", 0.8),
+		scored("Isa.40.31", 0.5),
+	})
+	if len(verses) != 2 || verses[0].VerseID != "Ps.23.1" || verses[0].Score != 0.8 {
+		t.Errorf("dedupeVerses = %v, want Ps.23.1 at 0.8 then Isa.40.31", verses)
+	}
+}
+
+func TestConsistencyDocumentsIncludesEnrichedRendering(t *testing.T) {
+	text := "Jesus wept."
+
+	bare, err := consistencyDocuments(text, nil)
+	if err != nil {
+		t.Fatalf("consistencyDocuments: %v", err)
+	}
+	if len(bare) != 1 || bare[0] != text {
+		t.Errorf("documents without annotations = %q, want [%q]", bare, text)
+	}
+
+	docs, err := consistencyDocuments(text, []models.VerseAnnotation{{Annotation: "compassion"}, {Annotation: "grief"}})
+	if err != nil {
+		t.Fatalf("consistencyDocuments: %v", err)
+	}
+	want := []string{text, "Jesus wept. [Themes: compassion, grief]"}
+	if len(docs) != 2 || docs[0] != want[0] || docs[1] != want[1] {
+		t.Errorf("documents with annotations = %q, want %q", docs, want)
+	}
+}
+
+func TestQueryTermsLinkForgivingAndForgave(t *testing.T) {
+	parser := newQueryParser(nil, nil, true)
+	words := func(query string) map[string]bool {
+		set := make(map[string]bool)
+		for _, term := range parser.terms(query) {
+			set[term.Word] = true
+		}
+		return set
+	}
+
+	forgiving, forgave := words("forgiving"), words("forgave")
+	if !forgiving["forgiv"] || !forgave["forgiv"] {
+		t.Errorf("terms for forgiving = %v and forgave = %v, want both to include forgiv", forgiving, forgave)
+	}
+}
+
+func TestFoldText(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"grâce", "grace"},
+		{"Grâce", "grace"},
+		{"ｇｒａｃｅ", "grace"}, // Full-width
+		{"Resurrección", "resurreccion"},
+		{"ﬁre", "fire"}, // Ligature
+		{"grace", "grace"},
+	}
+	for _, tt := range tests {
+		if got := foldText(tt.in); got != tt.want {
+			t.Errorf("foldText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	if words := splitWords("“grâce” and ｐｅａｃｅ"); len(words) != 3 || words[0] != "grace" || words[2] != "peace" {
+		t.Errorf("splitWords = %q, want [grace and peace]", words)
+	}
+}
+
+// stubTopicVersesRepo serves GetTopicsVerses from verses, counting calls
+type stubTopicVersesRepo struct {
+	repository.TopicRepository
+	verses map[string][]models.Citation
+	calls  int
+}
+
+func (r *stubTopicVersesRepo) GetTopicsVerses(_ context.Context, topicIDs []string, _ models.TopicVerseOptions) (map[string][]models.Citation, error) {
+	r.calls++
+	verses := make(map[string][]models.Citation)
+	for _, id := range topicIDs {
+		if v, ok := r.verses[id]; ok {
+			verses[id] = v
+		}
+	}
+	return verses, nil
+}
+
+func TestTopicVerseListFetchesOnceInTopicOrder(t *testing.T) {
+	repo := &stubTopicVersesRepo{verses: map[string][]models.Citation{
+		"grace": {{VerseID: "Eph.2.8"}, {VerseID: "Rom.3.24"}},
+		"faith": {{VerseID: "Heb.11.1"}, {VerseID: "Eph.2.8"}},
+	}}
+	svc := &VectorSearchService{topicRepo: repo}
+	topics := []models.ScoredTopic{{TopicID: "faith"}, {TopicID: "grace"}, {TopicID: "unknown"}}
+
+	list := svc.topicVerseList(context.Background(), topics, models.VerseSearchOptions{}, 0)
+
+	want := []string{"Heb.11.1", "Eph.2.8", "Rom.3.24"}
+	if len(list) != len(want) {
+		t.Fatalf("got %d verses %v, want %v", len(list), list, want)
+	}
+	for i, v := range list {
+		if v.VerseID != want[i] {
+			t.Errorf("verse %d = %s, want %s", i, v.VerseID, want[i])
+		}
+	}
+	if repo.calls != 1 {
+		t.Errorf("GetTopicsVerses called %d times, want 1", repo.calls)
+	}
+}
+
+func TestGroupTopicsByConceptFoldsAccents(t *testing.T) {
+	topics := []models.ScoredTopic{
+		{TopicID: "1", Name: "Grâce", Score: 1},
+		{TopicID: "2", Name: "Grace", Score: 0.95},
+	}
+	concepts := groupTopicsByConcept(topics, []string{"grace"}, 0.9)
+	if len(concepts) != 1 || len(concepts[0]) != 2 {
+		t.Errorf("concepts = %v, want Grâce and Grace in one concept", concepts)
+	}
+}
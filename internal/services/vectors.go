@@ -0,0 +1,64 @@
+package services
+
+import (
+	"math"
+
+	"github.com/sola-scriptura-search-api/internal/models"
+)
+
+// cosineSimilarity returns the cosine of the angle between a and b
+// Returns 0 for mismatched lengths or zero vectors
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// maximalMarginalRelevance greedily selects up to topK verses, trading each candidate's
+// query similarity against its closest already-selected verse. diversity is 1 - lambda:
+// 0 keeps pure relevance order, 1 picks purely for dissimilarity. Candidates missing an
+// embedding are treated as dissimilar to everything.
+func maximalMarginalRelevance(candidates []models.ScoredVerse, topK int, diversity float64) []models.ScoredVerse {
+	if topK > len(candidates) {
+		topK = len(candidates)
+	}
+
+	selected := make([]models.ScoredVerse, 0, topK)
+	used := make([]bool, len(candidates))
+	// maxSim[i] is candidate i's highest similarity to any selected verse
+	maxSim := make([]float64, len(candidates))
+
+	for len(selected) < topK {
+		best, bestScore := -1, math.Inf(-1)
+		for i, c := range candidates {
+			if used[i] {
+				continue
+			}
+			score := (1-diversity)*c.Score - diversity*maxSim[i]
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		used[best] = true
+		pick := candidates[best]
+		selected = append(selected, pick)
+		for i, c := range candidates {
+			if !used[i] {
+				maxSim[i] = math.Max(maxSim[i], cosineSimilarity(c.Embedding, pick.Embedding))
+			}
+		}
+	}
+	return selected
+}
@@ -0,0 +1,26 @@
+-- Migration: Store canonical topic definitions
+-- Created: 2026-10-14
+-- Purpose: Record the curated verse list (with importance tier) each topic is
+--          expected to contain, so coverage can be audited live via the API
+
+--------------------------------------------------------------------------------
+-- Table: topic_canonical_verses
+-- One row per verse a curated topic definition says should be mapped.
+-- Verses are keyed by OSIS id so definitions can reference verses that are
+-- missing from api.verses (those surface as gaps rather than failing the import).
+-- Populated from the scripts/audit JSON definitions with
+--   go run scripts/audit/main.go -store scripts/audit/definitions/*.json
+-- which replaces each defined topic's rows; topics without a definition have none.
+--------------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS api.topic_canonical_verses (
+    topic_id        INTEGER  NOT NULL REFERENCES api.topics(id) ON DELETE CASCADE,
+    osis_verse_id   TEXT     NOT NULL,
+    importance_tier SMALLINT NOT NULL DEFAULT 3,
+    PRIMARY KEY (topic_id, osis_verse_id)
+);
+
+COMMENT ON TABLE api.topic_canonical_verses IS
+    'Canonical verse definitions per topic: 1=essential, 2=important, 3=supporting';
+
+CREATE INDEX IF NOT EXISTS idx_topic_canonical_verses_tier
+    ON api.topic_canonical_verses (topic_id, importance_tier);
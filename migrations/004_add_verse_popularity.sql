@@ -0,0 +1,26 @@
+-- Migration: Add popularity to verses
+-- Created: 2026-10-14
+-- Purpose: Store a normalized popularity signal (how often a verse is returned
+--          or clicked) used as a gentle ranking tiebreaker between results
+--          with near-identical relevance scores
+
+--------------------------------------------------------------------------------
+-- Add popularity column to verses
+--------------------------------------------------------------------------------
+ALTER TABLE api.verses
+ADD COLUMN IF NOT EXISTS popularity REAL NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN api.verses.popularity IS
+    'Normalized usage popularity in [0,1], refreshed periodically from query logs';
+
+--------------------------------------------------------------------------------
+-- Refresh notes:
+-- Popularity is maintained by a periodic job rather than on the request path.
+-- Normalize so the most popular verse is 1.0, e.g. from a per-verse hit count:
+--   UPDATE api.verses v
+--   SET popularity = h.hits::real / MAX(h.hits) OVER ()
+--   FROM (SELECT verse_id, COUNT(*) AS hits FROM <query_log> GROUP BY verse_id) h
+--   WHERE v.id = h.verse_id;
+-- The API reads this column directly (not via mv_verses_search), so no view
+-- refresh is needed after updating it.
+--------------------------------------------------------------------------------
@@ -0,0 +1,29 @@
+-- Migration: Create pericope boundaries
+-- Created: 2026-10-14
+-- Purpose: Store passage (paragraph/thought unit) boundaries so search results
+--          can be expanded to the coherent passage containing a matched verse
+
+--------------------------------------------------------------------------------
+-- Table: pericopes
+-- Each row spans start_chapter:start_verse .. end_chapter:end_verse within one
+-- book (inclusive). Pericopes may cross chapter boundaries.
+--------------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS api.pericopes (
+    id            SERIAL   PRIMARY KEY,
+    book_id       INTEGER  NOT NULL REFERENCES api.books(id),
+    start_chapter INTEGER  NOT NULL,
+    start_verse   INTEGER  NOT NULL,
+    end_chapter   INTEGER  NOT NULL,
+    end_verse     INTEGER  NOT NULL,
+    title         TEXT,
+    CHECK ((start_chapter, start_verse) <= (end_chapter, end_verse))
+);
+
+CREATE INDEX IF NOT EXISTS idx_pericopes_book_range
+    ON api.pericopes (book_id, start_chapter, start_verse);
+
+--------------------------------------------------------------------------------
+-- Usage notes:
+-- Books without pericope rows fall back to a fixed +/-N verse window within the
+-- chapter (PERICOPE_FALLBACK_WINDOW), flagged as a fallback in API responses.
+--------------------------------------------------------------------------------
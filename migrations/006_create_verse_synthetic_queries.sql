@@ -0,0 +1,30 @@
+-- Migration: Store synthetic queries from enrichment
+-- Created: 2026-10-14
+-- Purpose: Keep the search queries the enrichment pipeline generates per verse
+--          so they can be embedded and upserted, or used to evaluate retrieval
+
+--------------------------------------------------------------------------------
+-- Table: verse_synthetic_queries
+-- One row per generated query. source records what produced it (e.g. the
+-- enrichment model) so queries from different runs can be told apart.
+--------------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS api.verse_synthetic_queries (
+    id         SERIAL      PRIMARY KEY,
+    verse_id   INTEGER     NOT NULL REFERENCES api.verses(id) ON DELETE CASCADE,
+    query      TEXT        NOT NULL,
+    source     TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (verse_id, query, source)
+);
+
+COMMENT ON TABLE api.verse_synthetic_queries IS
+    'Search queries generated per verse by enrichment, for embedding and retrieval evaluation';
+
+CREATE INDEX IF NOT EXISTS idx_verse_synthetic_queries_verse
+    ON api.verse_synthetic_queries (verse_id);
+
+--------------------------------------------------------------------------------
+-- Usage notes:
+-- Populated by scripts/enrichment/apply. Re-running it is safe: duplicate
+-- (verse_id, query, source) rows are skipped.
+--------------------------------------------------------------------------------
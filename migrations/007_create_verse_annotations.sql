@@ -0,0 +1,30 @@
+-- Migration: Store theological annotations from enrichment
+-- Created: 2026-10-14
+-- Purpose: Keep the theme labels enrichment assigns to each verse as structured
+--          data (rather than only folding them into the augmented embedding text)
+--          so the API can expose them and topic membership can be inferred
+
+--------------------------------------------------------------------------------
+-- Table: verse_annotations
+-- One row per label. source records what produced it (e.g. the enrichment model).
+--------------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS api.verse_annotations (
+    id         SERIAL      PRIMARY KEY,
+    verse_id   INTEGER     NOT NULL REFERENCES api.verses(id) ON DELETE CASCADE,
+    annotation TEXT        NOT NULL,
+    source     TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (verse_id, annotation, source)
+);
+
+COMMENT ON TABLE api.verse_annotations IS
+    'Theological theme labels per verse from enrichment, served by GET /verses/:osisID/annotations';
+
+CREATE INDEX IF NOT EXISTS idx_verse_annotations_verse
+    ON api.verse_annotations (verse_id);
+
+--------------------------------------------------------------------------------
+-- Usage notes:
+-- Populated by scripts/enrichment/apply alongside verse_synthetic_queries.
+-- Re-running it is safe: duplicate (verse_id, annotation, source) rows are skipped.
+--------------------------------------------------------------------------------
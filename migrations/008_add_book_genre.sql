@@ -0,0 +1,28 @@
+-- Migration: Add genre to books
+-- Created: 2026-10-14
+-- Purpose: Classify books by literary genre so tools (e.g. enrichment sampling)
+--          can select only wisdom literature, only epistles, etc.
+
+--------------------------------------------------------------------------------
+-- Add genre column to books
+--------------------------------------------------------------------------------
+ALTER TABLE api.books
+ADD COLUMN IF NOT EXISTS genre TEXT;
+
+COMMENT ON COLUMN api.books.genre IS
+    'Literary genre: law, history, wisdom, prophets, gospels, epistles, apocalyptic';
+
+UPDATE api.books SET genre = CASE
+    WHEN book_order BETWEEN 1 AND 5 THEN 'law'            -- Genesis-Deuteronomy
+    WHEN book_order BETWEEN 6 AND 17 THEN 'history'       -- Joshua-Esther
+    WHEN book_order BETWEEN 18 AND 22 THEN 'wisdom'       -- Job-Song of Songs
+    WHEN book_order BETWEEN 23 AND 39 THEN 'prophets'     -- Isaiah-Malachi
+    WHEN book_order BETWEEN 40 AND 43 THEN 'gospels'      -- Matthew-John
+    WHEN book_order = 44 THEN 'history'                   -- Acts
+    WHEN book_order BETWEEN 45 AND 65 THEN 'epistles'     -- Romans-Jude
+    WHEN book_order = 66 THEN 'apocalyptic'               -- Revelation
+END
+WHERE genre IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_books_genre
+    ON api.books (genre);
@@ -0,0 +1,40 @@
+-- Migration: Add augmented embedding to verses
+-- Created: 2026-10-14
+-- Purpose: Store the enriched (augmented-text) embedding that scripts/enrichment/apply
+--          upserts to Vertex AI, so pgvector can serve the same vectors without
+--          clobbering the base text embedding
+
+--------------------------------------------------------------------------------
+-- Add augmented_embedding column to verses
+--------------------------------------------------------------------------------
+ALTER TABLE api.verses
+ADD COLUMN IF NOT EXISTS augmented_embedding vector;
+
+COMMENT ON COLUMN api.verses.augmented_embedding IS
+    'Embedding of the verse text rendered with its theological annotations; NULL until enriched';
+
+--------------------------------------------------------------------------------
+-- Recreate mv_verses_search with the augmented embedding
+-- The API searches COALESCE(augmented_embedding, embedding) when
+-- PGVECTOR_USE_AUGMENTED_EMBEDDING is set; that expression is not covered by the
+-- ivfflat index, so those searches are exact scans
+--------------------------------------------------------------------------------
+DROP MATERIALIZED VIEW IF EXISTS api_views.mv_verses_search;
+
+CREATE MATERIALIZED VIEW api_views.mv_verses_search AS
+SELECT
+    v.osis_verse_id AS verse_id,
+    b.osis_id AS book,
+    b.book_order,
+    v.chapter,
+    v.verse,
+    v.text,
+    v.embedding,
+    v.augmented_embedding
+FROM api.verses v
+JOIN api.books b ON v.book_id = b.id
+WHERE v.embedding IS NOT NULL;
+
+CREATE INDEX idx_mv_verses_search_embedding ON api_views.mv_verses_search
+    USING ivfflat (embedding vector_cosine_ops) WITH (lists = 100);
+CREATE INDEX idx_mv_verses_search_verse_id ON api_views.mv_verses_search (verse_id);
@@ -0,0 +1,221 @@
+package osis
+
+import "strings"
+
+// Book is a canonical book with its OSIS id and position in the canon
+type Book struct {
+	ID       string // OSIS book id, e.g. "1Cor"
+	Name     string // English name, e.g. "1 Corinthians"
+	Order    int    // Canonical position, 1 (Genesis) to 66 (Revelation)
+	Chapters int    // Number of chapters
+}
+
+// SingleChapter reports whether the book has only one chapter (e.g. Jude), where a
+// lone number in a reference is a verse rather than a chapter
+func (b Book) SingleChapter() bool {
+	return b.Chapters == 1
+}
+
+// Books lists the 66 books of the Protestant canon in canonical order
+var Books = []Book{
+	{"Gen", "Genesis", 1, 50},
+	{"Exod", "Exodus", 2, 40},
+	{"Lev", "Leviticus", 3, 27},
+	{"Num", "Numbers", 4, 36},
+	{"Deut", "Deuteronomy", 5, 34},
+	{"Josh", "Joshua", 6, 24},
+	{"Judg", "Judges", 7, 21},
+	{"Ruth", "Ruth", 8, 4},
+	{"1Sam", "1 Samuel", 9, 31},
+	{"2Sam", "2 Samuel", 10, 24},
+	{"1Kgs", "1 Kings", 11, 22},
+	{"2Kgs", "2 Kings", 12, 25},
+	{"1Chr", "1 Chronicles", 13, 29},
+	{"2Chr", "2 Chronicles", 14, 36},
+	{"Ezra", "Ezra", 15, 10},
+	{"Neh", "Nehemiah", 16, 13},
+	{"Esth", "Esther", 17, 10},
+	{"Job", "Job", 18, 42},
+	{"Ps", "Psalms", 19, 150},
+	{"Prov", "Proverbs", 20, 31},
+	{"Eccl", "Ecclesiastes", 21, 12},
+	{"Song", "Song of Songs", 22, 8},
+	{"Isa", "Isaiah", 23, 66},
+	{"Jer", "Jeremiah", 24, 52},
+	{"Lam", "Lamentations", 25, 5},
+	{"Ezek", "Ezekiel", 26, 48},
+	{"Dan", "Daniel", 27, 12},
+	{"Hos", "Hosea", 28, 14},
+	{"Joel", "Joel", 29, 3},
+	{"Amos", "Amos", 30, 9},
+	{"Obad", "Obadiah", 31, 1},
+	{"Jonah", "Jonah", 32, 4},
+	{"Mic", "Micah", 33, 7},
+	{"Nah", "Nahum", 34, 3},
+	{"Hab", "Habakkuk", 35, 3},
+	{"Zeph", "Zephaniah", 36, 3},
+	{"Hag", "Haggai", 37, 2},
+	{"Zech", "Zechariah", 38, 14},
+	{"Mal", "Malachi", 39, 4},
+	{"Matt", "Matthew", 40, 28},
+	{"Mark", "Mark", 41, 16},
+	{"Luke", "Luke", 42, 24},
+	{"John", "John", 43, 21},
+	{"Acts", "Acts", 44, 28},
+	{"Rom", "Romans", 45, 16},
+	{"1Cor", "1 Corinthians", 46, 16},
+	{"2Cor", "2 Corinthians", 47, 13},
+	{"Gal", "Galatians", 48, 6},
+	{"Eph", "Ephesians", 49, 6},
+	{"Phil", "Philippians", 50, 4},
+	{"Col", "Colossians", 51, 4},
+	{"1Thess", "1 Thessalonians", 52, 5},
+	{"2Thess", "2 Thessalonians", 53, 3},
+	{"1Tim", "1 Timothy", 54, 6},
+	{"2Tim", "2 Timothy", 55, 4},
+	{"Titus", "Titus", 56, 3},
+	{"Phlm", "Philemon", 57, 1},
+	{"Heb", "Hebrews", 58, 13},
+	{"Jas", "James", 59, 5},
+	{"1Pet", "1 Peter", 60, 5},
+	{"2Pet", "2 Peter", 61, 3},
+	{"1John", "1 John", 62, 5},
+	{"2John", "2 John", 63, 1},
+	{"3John", "3 John", 64, 1},
+	{"Jude", "Jude", 65, 1},
+	{"Rev", "Revelation", 66, 22},
+}
+
+// aliases maps extra normalized names and abbreviations to OSIS ids
+// OSIS ids and full names are matched without being listed here. Entries settle
+// abbreviations that would otherwise be ambiguous prefixes (e.g. "jn", "phil").
+var aliases = map[string]string{
+	"ge": "Gen", "gn": "Gen",
+	"ex": "Exod", "exo": "Exod",
+	"le": "Lev", "lv": "Lev",
+	"nu": "Num", "nm": "Num",
+	"dt":  "Deut",
+	"jos": "Josh", "jsh": "Josh",
+	"jdg": "Judg", "jg": "Judg",
+	"rth": "Ruth", "ru": "Ruth",
+	"1sa": "1Sam", "2sa": "2Sam",
+	"1ki": "1Kgs", "1kin": "1Kgs", "1kings": "1Kgs", "2ki": "2Kgs", "2kin": "2Kgs", "2kings": "2Kgs",
+	"1ch": "1Chr", "1chron": "1Chr", "2ch": "2Chr", "2chron": "2Chr",
+	"ne": "Neh",
+	"es": "Esth", "est": "Esth",
+	"jb":  "Job",
+	"psa": "Ps", "psalm": "Ps", "pss": "Ps", "psm": "Ps",
+	"pr": "Prov", "prv": "Prov",
+	"ec": "Eccl", "ecc": "Eccl", "qoh": "Eccl",
+	"so": "Song", "sos": "Song", "songofsolomon": "Song", "canticles": "Song",
+	"is":  "Isa",
+	"je":  "Jer",
+	"la":  "Lam",
+	"eze": "Ezek", "ezk": "Ezek",
+	"da": "Dan", "dn": "Dan",
+	"ho":  "Hos",
+	"jl":  "Joel",
+	"am":  "Amos",
+	"ob":  "Obad",
+	"jnh": "Jonah", "jon": "Jonah",
+	"mi":  "Mic",
+	"na":  "Nah",
+	"hb":  "Hab",
+	"zep": "Zeph", "zp": "Zeph",
+	"hg":  "Hag",
+	"zec": "Zech", "zc": "Zech",
+	"ml": "Mal",
+	"mt": "Matt",
+	"mk": "Mark", "mr": "Mark", "mrk": "Mark",
+	"lk": "Luke", "lu": "Luke",
+	"jn": "John", "jhn": "John",
+	"ac": "Acts",
+	"ro": "Rom", "rm": "Rom",
+	"1co": "1Cor", "2co": "2Cor",
+	"ga":  "Gal",
+	"ep":  "Eph",
+	"php": "Phil", "pp": "Phil",
+	"co":  "Col",
+	"1th": "1Thess", "1thes": "1Thess", "2th": "2Thess", "2thes": "2Thess",
+	"1ti": "1Tim", "2ti": "2Tim",
+	"tit": "Titus",
+	"phm": "Phlm", "philem": "Phlm",
+	"jm":  "Jas",
+	"1pe": "1Pet", "1pt": "1Pet", "2pe": "2Pet", "2pt": "2Pet",
+	"1jn": "1John", "1jo": "1John", "2jn": "2John", "2jo": "2John", "3jn": "3John", "3jo": "3John",
+	"jud": "Jude", "jd": "Jude",
+	"re": "Rev", "rv": "Rev", "revelations": "Rev", "apocalypse": "Rev",
+}
+
+var (
+	booksByID  = make(map[string]Book, len(Books))
+	booksByKey = make(map[string]Book, len(Books)*3)
+)
+
+func init() {
+	for _, b := range Books {
+		booksByID[b.ID] = b
+		booksByKey[normalizeBookName(b.ID)] = b
+		booksByKey[normalizeBookName(b.Name)] = b
+	}
+	for alias, id := range aliases {
+		booksByKey[alias] = booksByID[id]
+	}
+}
+
+// BookByID returns the book with an exact OSIS id
+func BookByID(id string) (Book, bool) {
+	b, ok := booksByID[id]
+	return b, ok
+}
+
+// LookupBook resolves a human book name or abbreviation, e.g. "1 Cor", "Romans", "Jn"
+// Exact names, OSIS ids and known abbreviations match first; otherwise the name must
+// be a prefix of exactly one book's name or id. Returns ErrUnknownBook or
+// ErrAmbiguousBook when it cannot be resolved to a single book.
+func LookupBook(name string) (Book, error) {
+	key := normalizeBookName(name)
+	if key == "" {
+		return Book{}, ErrUnknownBook
+	}
+	if b, ok := booksByKey[key]; ok {
+		return b, nil
+	}
+
+	var match Book
+	matches := 0
+	for _, b := range Books {
+		if strings.HasPrefix(normalizeBookName(b.Name), key) || strings.HasPrefix(strings.ToLower(b.ID), key) {
+			match = b
+			matches++
+		}
+	}
+	switch {
+	case matches == 1 && len(key) >= 2:
+		return match, nil
+	case matches > 1:
+		return Book{}, ErrAmbiguousBook
+	default:
+		return Book{}, ErrUnknownBook
+	}
+}
+
+// ordinalPrefixes rewrites spelled-out and roman numbered-book prefixes to digits
+var ordinalPrefixes = []struct{ prefix, digit string }{
+	{"first ", "1"}, {"second ", "2"}, {"third ", "3"},
+	{"iii ", "3"}, {"ii ", "2"}, {"i ", "1"},
+	{"1st ", "1"}, {"2nd ", "2"}, {"3rd ", "3"},
+}
+
+// normalizeBookName lowercases a book name and strips spaces and periods,
+// mapping numbered prefixes like "II " or "First " to digits
+func normalizeBookName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, p := range ordinalPrefixes {
+		if strings.HasPrefix(name, p.prefix) {
+			name = p.digit + name[len(p.prefix):]
+			break
+		}
+	}
+	return strings.NewReplacer(" ", "", ".", "").Replace(name)
+}
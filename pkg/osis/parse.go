@@ -0,0 +1,187 @@
+package osis
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidReference is returned for text that is not a recognizable reference
+	ErrInvalidReference = errors.New("invalid reference")
+	// ErrUnknownBook is returned when a book name matches no book
+	ErrUnknownBook = errors.New("unknown book")
+	// ErrAmbiguousBook is returned when an abbreviation matches several books, e.g. "Ju"
+	ErrAmbiguousBook = errors.New("ambiguous book abbreviation")
+)
+
+// Verse is a position in the canon; Verse 0 means the whole chapter
+type Verse struct {
+	Book    string // OSIS book id
+	Chapter int
+	Verse   int
+}
+
+// OSIS returns the canonical id, e.g. "John.3.16", or "John.3" for a whole chapter
+func (v Verse) OSIS() string {
+	if v.Verse == 0 {
+		return fmt.Sprintf("%s.%d", v.Book, v.Chapter)
+	}
+	return fmt.Sprintf("%s.%d.%d", v.Book, v.Chapter, v.Verse)
+}
+
+// before reports whether v comes before w within the same book
+func (v Verse) before(w Verse) bool {
+	if v.Chapter != w.Chapter {
+		return v.Chapter < w.Chapter
+	}
+	return v.Verse < w.Verse
+}
+
+// Reference is a single verse, chapter or inclusive range of them
+// Start equals End for a single verse or chapter
+type Reference struct {
+	Start Verse
+	End   Verse
+}
+
+// IsRange reports whether the reference spans more than one verse or chapter
+func (r Reference) IsRange() bool {
+	return r.Start != r.End
+}
+
+// IsVerse reports whether the reference is exactly one verse
+func (r Reference) IsVerse() bool {
+	return !r.IsRange() && r.Start.Verse > 0
+}
+
+// CrossesBooks reports whether the range starts and ends in different books
+func (r Reference) CrossesBooks() bool {
+	return r.Start.Book != r.End.Book
+}
+
+// OSIS returns the canonical form, e.g. "John.3.16" or "Rom.8.28-Rom.8.30"
+func (r Reference) OSIS() string {
+	if !r.IsRange() {
+		return r.Start.OSIS()
+	}
+	return r.Start.OSIS() + "-" + r.End.OSIS()
+}
+
+var (
+	// osisPoint matches the OSIS form Book.Chapter[.Verse], e.g. "1Cor.13.4"
+	osisPoint = regexp.MustCompile(`^([1-3]?[A-Za-z]+)\.(\d+)(?:\.(\d+))?$`)
+	// humanPoint matches a book name followed by chapter[:verse], e.g. "1 Cor 13:4"
+	humanPoint = regexp.MustCompile(`^(.*?[A-Za-z])\.?\s*(\d+)(?:\s*[:.]\s*(\d+))?$`)
+	// chapterVerse matches a bare chapter:verse, e.g. "4:2"
+	chapterVerse = regexp.MustCompile(`^(\d+)\s*[:.]\s*(\d+)$`)
+	// number matches a bare chapter or verse number
+	number = regexp.MustCompile(`^\d+$`)
+)
+
+// Parse converts a human or OSIS reference into canonical form
+// Accepted forms include "John 3:16", "Jn 3.16", "John.3.16", "Rom 8:28-30",
+// "John 3:16-4:2", "1 Cor 13", "Jude 3" (single-chapter books take a lone number as
+// the verse) and "Rom.8.28-Rom.8.30". Ranges may cross books; callers that need a
+// single book should check CrossesBooks.
+func Parse(ref string) (Reference, error) {
+	ref = strings.TrimSpace(strings.NewReplacer("–", "-", "—", "-").Replace(ref))
+	if ref == "" {
+		return Reference{}, ErrInvalidReference
+	}
+
+	left, right, isRange := strings.Cut(ref, "-")
+	start, book, err := parsePoint(strings.TrimSpace(left))
+	if err != nil {
+		return Reference{}, err
+	}
+	if !isRange {
+		return Reference{Start: start, End: start}, nil
+	}
+
+	end, endBook, err := parseRangeEnd(strings.TrimSpace(right), start, book)
+	if err != nil {
+		return Reference{}, err
+	}
+	if (start.Verse == 0) != (end.Verse == 0) {
+		return Reference{}, fmt.Errorf("%w: range %q mixes chapters and verses", ErrInvalidReference, ref)
+	}
+	if endBook.Order < book.Order || (start.Book == end.Book && end.before(start)) {
+		return Reference{}, fmt.Errorf("%w: range %q ends before it starts", ErrInvalidReference, ref)
+	}
+	return Reference{Start: start, End: end}, nil
+}
+
+// ParseVerse parses a reference that must name exactly one verse
+func ParseVerse(ref string) (Verse, error) {
+	r, err := Parse(ref)
+	if err != nil {
+		return Verse{}, err
+	}
+	if !r.IsVerse() {
+		return Verse{}, fmt.Errorf("%w: %q is not a single verse", ErrInvalidReference, ref)
+	}
+	return r.Start, nil
+}
+
+// parsePoint parses a reference with a book, returning the resolved book too
+func parsePoint(s string) (Verse, Book, error) {
+	if m := osisPoint.FindStringSubmatch(s); m != nil {
+		if book, ok := BookByID(m[1]); ok {
+			v, err := point(book, m[2], m[3])
+			return v, book, err
+		}
+	}
+
+	m := humanPoint.FindStringSubmatch(s)
+	if m == nil {
+		return Verse{}, Book{}, fmt.Errorf("%w: %q", ErrInvalidReference, s)
+	}
+	book, err := LookupBook(m[1])
+	if err != nil {
+		return Verse{}, Book{}, fmt.Errorf("%w: %q", err, m[1])
+	}
+	v, err := point(book, m[2], m[3])
+	return v, book, err
+}
+
+// parseRangeEnd parses the part after "-", which may omit the book and chapter
+// "30" continues the start's unit (a verse if start has one, else a chapter),
+// "4:2" is a chapter and verse in the same book, anything else must name a book
+func parseRangeEnd(s string, start Verse, book Book) (Verse, Book, error) {
+	var v Verse
+	var err error
+	switch {
+	case number.MatchString(s):
+		if start.Verse > 0 {
+			v, err = point(book, strconv.Itoa(start.Chapter), s)
+		} else {
+			v, err = point(book, s, "")
+		}
+	case chapterVerse.MatchString(s):
+		m := chapterVerse.FindStringSubmatch(s)
+		v, err = point(book, m[1], m[2])
+	default:
+		return parsePoint(s)
+	}
+	return v, book, err
+}
+
+// point validates a chapter and optional verse against book
+// A single-chapter book given only one number treats it as the verse
+func point(book Book, chapter, verse string) (Verse, error) {
+	c, _ := strconv.Atoi(chapter)
+	v, _ := strconv.Atoi(verse)
+	if book.SingleChapter() && verse == "" {
+		c, v = 1, c
+	}
+	if c < 1 || c > book.Chapters {
+		return Verse{}, fmt.Errorf("%w: %s has %d chapters", ErrInvalidReference, book.Name, book.Chapters)
+	}
+	if (verse != "" || book.SingleChapter()) && v < 1 {
+		return Verse{}, fmt.Errorf("%w: verse must be at least 1", ErrInvalidReference)
+	}
+	return Verse{Book: book.ID, Chapter: c, Verse: v}, nil
+}
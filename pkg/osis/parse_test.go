@@ -0,0 +1,73 @@
+package osis
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want string // Canonical OSIS form
+	}{
+		{"John 3:16", "John.3.16"},
+		{"John.3.16", "John.3.16"},
+		{"Jn 3.16", "John.3.16"},
+		{"1 Cor 13", "1Cor.13"},
+		// Single-chapter books take a lone number as the verse
+		{"Jude 3", "Jude.1.3"},
+		{"Obad 1", "Obad.1.1"},
+		{"Rom 8:28-30", "Rom.8.28-Rom.8.30"},
+		{"John 3:16-4:2", "John.3.16-John.4.2"},
+		{"Rom.8.28-Rom.8.30", "Rom.8.28-Rom.8.30"},
+		{"Mal 4:5-Matt 1:1", "Mal.4.5-Matt.1.1"},
+		{"I John 4:8", "1John.4.8"},
+		{"First John 4:8", "1John.4.8"},
+		{"1 John 4:8", "1John.4.8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := Parse(tt.ref)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.ref, err)
+			}
+			if got.OSIS() != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.ref, got.OSIS(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want error
+	}{
+		{"", ErrInvalidReference},
+		{"John 3:18-16", ErrInvalidReference},     // Reversed within a chapter
+		{"John 4:2-3:16", ErrInvalidReference},    // Reversed across chapters
+		{"Matt 1:1-Mal 4:5", ErrInvalidReference}, // Reversed across books
+		{"Ju 1:1", ErrAmbiguousBook},              // Jude, Judges
+		{"Hezekiah 1:1", ErrUnknownBook},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			if _, err := Parse(tt.ref); !errors.Is(err, tt.want) {
+				t.Errorf("Parse(%q) error = %v, want %v", tt.ref, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVerse(t *testing.T) {
+	v, err := ParseVerse("Jude 3")
+	if err != nil || v != (Verse{Book: "Jude", Chapter: 1, Verse: 3}) {
+		t.Errorf("ParseVerse(Jude 3) = %v, %v; want Jude.1.3", v, err)
+	}
+
+	for _, ref := range []string{"John 3", "Rom 8:28-30"} {
+		if _, err := ParseVerse(ref); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("ParseVerse(%q) error = %v, want ErrInvalidReference", ref, err)
+		}
+	}
+}
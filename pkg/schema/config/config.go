@@ -4,22 +4,44 @@ import (
 	"os"
 	"strconv"
 	"sync"
+	"time"
 )
 
 // Config holds configuration for database and embedding operations
 type Config struct {
 	// PostgreSQL
 	PostgresURI string
+	// Connection pool sizing; size it to the instance's max_connections divided by replicas
+	PGMaxOpenConns    int
+	PGMaxIdleConns    int
+	PGConnMaxLifetime time.Duration
+	PGConnMaxIdleTime time.Duration
 
 	// Embeddings
-	EmbeddingProvider   string // "vertex" or "custom"
+	EmbeddingProvider   string // "vertex", "custom" or "openai"
 	EmbeddingServiceURL string // For custom provider
-	EmbeddingDimensions int
+	// Overall per-request timeout for the custom and OpenAI embedding services
+	EmbeddingServiceTimeout time.Duration
+	EmbeddingDimensions     int
+	// Text template used to build the document text for every verse embedding.
+	// Go text/template with .Text (verse text) and .Themes (annotations, may be empty)
+	EmbeddingTextTemplate string
+	// In-process cache of query embeddings (size 0 disables)
+	EmbeddingCacheSize int
+	EmbeddingCacheTTL  time.Duration
+	// Scale every query and document embedding to unit length (for cosine indexes fed
+	// by embedders that do not normalize). Changing it requires re-embedding the corpus.
+	NormalizeEmbeddings bool
 
 	// Vertex AI (when EmbeddingProvider = "vertex")
 	GCPProjectID string
 	GCPLocation  string
 	VertexModel  string
+
+	// OpenAI (when EmbeddingProvider = "openai")
+	OpenAIAPIKey  string
+	OpenAIModel   string
+	OpenAIBaseURL string
 }
 
 var (
@@ -38,17 +60,32 @@ func GetConfig() *Config {
 func loadConfig() *Config {
 	return &Config{
 		// PostgreSQL
-		PostgresURI: getEnv("POSTGRES_URI", ""),
+		PostgresURI:       getEnv("POSTGRES_URI", ""),
+		PGMaxOpenConns:    getEnvInt("PG_MAX_OPEN_CONNS", 25),
+		PGMaxIdleConns:    getEnvInt("PG_MAX_IDLE_CONNS", 25),
+		PGConnMaxLifetime: getEnvDuration("PG_CONN_MAX_LIFETIME", 5*time.Minute),
+		PGConnMaxIdleTime: getEnvDuration("PG_CONN_MAX_IDLE_TIME", time.Minute),
 
 		// Embeddings
-		EmbeddingProvider:   getEnv("EMBEDDING_PROVIDER", "vertex"),
-		EmbeddingServiceURL: getEnv("EMBEDDING_SERVICE_URL", "http://localhost:8001"),
-		EmbeddingDimensions: getEnvInt("EMBEDDING_DIMENSIONS", 3072),
+		EmbeddingProvider:       getEnv("EMBEDDING_PROVIDER", "vertex"),
+		EmbeddingServiceURL:     getEnv("EMBEDDING_SERVICE_URL", "http://localhost:8001"),
+		EmbeddingServiceTimeout: getEnvDuration("EMBEDDING_SERVICE_TIMEOUT", 10*time.Second),
+		EmbeddingDimensions:     getEnvInt("EMBEDDING_DIMENSIONS", 3072),
+		EmbeddingTextTemplate: getEnv("EMBEDDING_TEXT_TEMPLATE",
+			`{{.Text}}{{if .Themes}} [Themes: {{join .Themes ", "}}]{{end}}`),
+		EmbeddingCacheSize:  getEnvInt("EMBEDDING_CACHE_SIZE", 1000),
+		EmbeddingCacheTTL:   getEnvDuration("EMBEDDING_CACHE_TTL", time.Hour),
+		NormalizeEmbeddings: getEnvBool("NORMALIZE_EMBEDDINGS", false),
 
 		// Vertex AI
 		GCPProjectID: getEnv("GCP_PROJECT_ID", ""),
 		GCPLocation:  getEnv("GCP_LOCATION", "us-central1"),
 		VertexModel:  getEnv("VERTEX_MODEL", "gemini-embedding-001"),
+
+		// OpenAI
+		OpenAIAPIKey:  getEnv("OPENAI_API_KEY", ""),
+		OpenAIModel:   getEnv("OPENAI_EMBEDDING_MODEL", "text-embedding-3-large"),
+		OpenAIBaseURL: getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
 	}
 }
 
@@ -69,3 +106,25 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return defaultValue
+		}
+		return b
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return defaultValue
+		}
+		return d
+	}
+	return defaultValue
+}
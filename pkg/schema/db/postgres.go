@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"sync"
-	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
@@ -39,10 +38,10 @@ func InitPostgres(ctx context.Context) error {
 		}
 
 		// Configure connection pool
-		pgDB.SetMaxOpenConns(25)
-		pgDB.SetMaxIdleConns(25)
-		pgDB.SetConnMaxLifetime(5 * time.Minute)
-		pgDB.SetConnMaxIdleTime(1 * time.Minute)
+		pgDB.SetMaxOpenConns(cfg.PGMaxOpenConns)
+		pgDB.SetMaxIdleConns(cfg.PGMaxIdleConns)
+		pgDB.SetConnMaxLifetime(cfg.PGConnMaxLifetime)
+		pgDB.SetConnMaxIdleTime(cfg.PGConnMaxIdleTime)
 
 		// Verify connectivity
 		if err := pgDB.PingContext(ctx); err != nil {
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"time"
 
 	"github.com/sola-scriptura-search-api/pkg/schema/config"
 )
@@ -17,14 +20,40 @@ type CustomEmbedder struct {
 	httpClient *http.Client
 }
 
+// customDialTimeout bounds connecting to the embedding service
+const customDialTimeout = 5 * time.Second
+
 // NewCustomEmbedder creates a new custom HTTP embedder
+// Requests time out after cfg.EmbeddingServiceTimeout, so a hung service cannot block
+// callers indefinitely even without a context deadline
 func NewCustomEmbedder(cfg *config.Config) *CustomEmbedder {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: customDialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.ResponseHeaderTimeout = cfg.EmbeddingServiceTimeout
+
 	return &CustomEmbedder{
-		cfg:        cfg,
-		httpClient: &http.Client{},
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout:   cfg.EmbeddingServiceTimeout,
+			Transport: transport,
+		},
+	}
+}
+
+// callError wraps a failed request, naming the timeout when one was hit
+func (e *CustomEmbedder) callError(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("embedding service timed out after %s: %w", e.cfg.EmbeddingServiceTimeout, err)
 	}
+	return fmt.Errorf("failed to call embedding service: %w", err)
 }
 
+// taskTypeToInstruction holds the instruction prefixes for the instruction-tuned model.
+// These must match exactly what the corpus was embedded with: the stored verse embeddings
+// (which scripts/export and scripts/upsert copy verbatim to Vertex) were produced with the
+// TaskTypeDocument instruction, and queries must use TaskTypeQuery. Any edit here requires
+// re-embedding the corpus; set EMBEDDING_CHECK_VERSE to detect drift at API startup.
 var taskTypeToInstruction = map[TaskType]string{
 	TaskTypeQuery:    "Represent the question for retrieving relevant Bible verses: ",
 	TaskTypeDocument: "Represent the Bible verse for retrieval: ",
@@ -75,7 +104,7 @@ func (e *CustomEmbedder) Embed(ctx context.Context, text string, taskType TaskTy
 
 	resp, err := e.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call embedding service: %w", err)
+		return nil, e.callError(err)
 	}
 	defer resp.Body.Close()
 
@@ -119,7 +148,7 @@ func (e *CustomEmbedder) EmbedBatch(ctx context.Context, texts []string, taskTyp
 
 	resp, err := e.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call embedding service: %w", err)
+		return nil, e.callError(err)
 	}
 	defer resp.Body.Close()
 
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sola-scriptura-search-api/pkg/schema/config"
+)
+
+func TestCustomEmbedderTimesOutSlowServer(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+	defer close(release) // Unblock the handler before Close waits on it
+
+	embedder := NewCustomEmbedder(&config.Config{
+		EmbeddingServiceURL:     server.URL,
+		EmbeddingServiceTimeout: 50 * time.Millisecond,
+	})
+
+	start := time.Now()
+	_, err := embedder.Embed(context.Background(), "grace", TaskTypeQuery)
+	if err == nil {
+		t.Fatal("Embed succeeded against a server slower than the timeout")
+	}
+	if !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Errorf("error = %v, want it to name the timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Embed returned after %s, want about the 50ms timeout", elapsed)
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/sola-scriptura-search-api/pkg/schema/config"
+)
+
+const (
+	openAIBatchLimit = 2048
+)
+
+// openAIModelDimensions is each embedding model's native (and maximum) dimensions
+// The text-embedding-3 models can return fewer dimensions on request; ada-002 cannot
+var openAIModelDimensions = map[string]int{
+	"text-embedding-3-large": 3072,
+	"text-embedding-3-small": 1536,
+	"text-embedding-ada-002": 1536,
+}
+
+// OpenAIEmbedder implements Embedder using the OpenAI embeddings API
+// OpenAI has no task types, so queries and documents are embedded identically
+type OpenAIEmbedder struct {
+	cfg        *config.Config
+	httpClient *http.Client
+	// dimensions is sent with each request when the model supports shortening
+	dimensions int
+}
+
+// NewOpenAIEmbedder creates a new OpenAI embedder
+// Fails if the model cannot produce cfg.EmbeddingDimensions-length vectors, since the
+// index dimensions are fixed
+func NewOpenAIEmbedder(cfg *config.Config) (*OpenAIEmbedder, error) {
+	if cfg.OpenAIAPIKey == "" {
+		return nil, fmt.Errorf("OPENAI_API_KEY is required for OpenAI embeddings")
+	}
+
+	e := &OpenAIEmbedder{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: cfg.EmbeddingServiceTimeout},
+	}
+	if native, ok := openAIModelDimensions[cfg.OpenAIModel]; ok {
+		switch {
+		case cfg.EmbeddingDimensions > native:
+			return nil, fmt.Errorf("%s produces at most %d dimensions, but EMBEDDING_DIMENSIONS is %d",
+				cfg.OpenAIModel, native, cfg.EmbeddingDimensions)
+		case cfg.EmbeddingDimensions < native && !strings.HasPrefix(cfg.OpenAIModel, "text-embedding-3"):
+			return nil, fmt.Errorf("%s only produces %d dimensions, but EMBEDDING_DIMENSIONS is %d",
+				cfg.OpenAIModel, native, cfg.EmbeddingDimensions)
+		case cfg.EmbeddingDimensions < native:
+			e.dimensions = cfg.EmbeddingDimensions
+		}
+	} else {
+		// Unknown models are asked for the configured size and checked on every response
+		e.dimensions = cfg.EmbeddingDimensions
+	}
+	return e, nil
+}
+
+type openAIEmbeddingRequest struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+	EncodingFormat string   `json:"encoding_format"`
+}
+
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+}
+
+type openAIErrorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Embed generates an embedding for a single text
+func (e *OpenAIEmbedder) Embed(ctx context.Context, text string, taskType TaskType) ([]float64, error) {
+	embeddings, err := e.embedBatchInternal(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch generates embeddings for multiple texts
+func (e *OpenAIEmbedder) EmbedBatch(ctx context.Context, texts []string, taskType TaskType) ([][]float64, error) {
+	if len(texts) == 0 {
+		return [][]float64{}, nil
+	}
+
+	allEmbeddings := make([][]float64, 0, len(texts))
+	for i := 0; i < len(texts); i += openAIBatchLimit {
+		end := min(i+openAIBatchLimit, len(texts))
+		batch, err := e.embedBatchInternal(ctx, texts[i:end])
+		if err != nil {
+			return nil, err
+		}
+		allEmbeddings = append(allEmbeddings, batch...)
+	}
+	return allEmbeddings, nil
+}
+
+func (e *OpenAIEmbedder) embedBatchInternal(ctx context.Context, texts []string) ([][]float64, error) {
+	jsonBody, err := json.Marshal(openAIEmbeddingRequest{
+		Model:          e.cfg.OpenAIModel,
+		Input:          texts,
+		Dimensions:     e.dimensions,
+		EncodingFormat: "float",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := strings.TrimSuffix(e.cfg.OpenAIBaseURL, "/") + "/embeddings"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.cfg.OpenAIAPIKey)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, fmt.Errorf("OpenAI embeddings timed out after %s: %w", e.cfg.EmbeddingServiceTimeout, err)
+		}
+		return nil, fmt.Errorf("failed to call OpenAI embeddings: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		var apiErr openAIErrorResponse
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("OpenAI embeddings error (%d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("OpenAI embeddings error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var embResp openAIEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(embResp.Data) != len(texts) {
+		return nil, fmt.Errorf("OpenAI returned %d embeddings for %d texts", len(embResp.Data), len(texts))
+	}
+
+	// Results carry their input index; place them explicitly rather than trusting order
+	embeddings := make([][]float64, len(texts))
+	for _, d := range embResp.Data {
+		if d.Index < 0 || d.Index >= len(texts) || embeddings[d.Index] != nil {
+			return nil, fmt.Errorf("OpenAI returned embedding index %d for %d texts", d.Index, len(texts))
+		}
+		if len(d.Embedding) != e.cfg.EmbeddingDimensions {
+			return nil, fmt.Errorf("OpenAI returned %d dimensions, expected %d", len(d.Embedding), e.cfg.EmbeddingDimensions)
+		}
+		embeddings[d.Index] = d.Embedding
+	}
+	return embeddings, nil
+}
@@ -3,11 +3,16 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
 
 	aiplatform "cloud.google.com/go/aiplatform/apiv1"
 	"cloud.google.com/go/aiplatform/apiv1/aiplatformpb"
 	"github.com/sola-scriptura-search-api/pkg/schema/config"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
@@ -16,10 +21,16 @@ const (
 )
 
 // VertexEmbedder implements Embedder using Google Cloud Vertex AI
+// The prediction client is long-lived; if its connection goes bad it is recreated
+// once on the failing request rather than requiring a process restart
 type VertexEmbedder struct {
-	cfg      *config.Config
-	client   *aiplatform.PredictionClient
-	endpoint string
+	cfg        *config.Config
+	mu         sync.RWMutex
+	client     *aiplatform.PredictionClient
+	endpoint   string
+	reconnects atomic.Int64
+
+	onReconnect func() // Called after each reconnect, if set
 }
 
 // NewVertexEmbedder creates a new Vertex AI embedder
@@ -28,10 +39,9 @@ func NewVertexEmbedder(ctx context.Context, cfg *config.Config) (*VertexEmbedder
 		return nil, fmt.Errorf("GCP_PROJECT_ID is required for Vertex AI embeddings")
 	}
 
-	clientEndpoint := fmt.Sprintf("%s-aiplatform.googleapis.com:443", cfg.GCPLocation)
-	client, err := aiplatform.NewPredictionClient(ctx, option.WithEndpoint(clientEndpoint))
+	client, err := newPredictionClient(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Vertex AI client: %w", err)
+		return nil, err
 	}
 
 	endpoint := fmt.Sprintf("projects/%s/locations/%s/publishers/google/models/%s",
@@ -44,14 +54,60 @@ func NewVertexEmbedder(ctx context.Context, cfg *config.Config) (*VertexEmbedder
 	}, nil
 }
 
+// newPredictionClient dials the regional Vertex AI prediction endpoint
+func newPredictionClient(ctx context.Context, cfg *config.Config) (*aiplatform.PredictionClient, error) {
+	clientEndpoint := fmt.Sprintf("%s-aiplatform.googleapis.com:443", cfg.GCPLocation)
+	client, err := aiplatform.NewPredictionClient(ctx, option.WithEndpoint(clientEndpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Vertex AI client: %w", err)
+	}
+	return client, nil
+}
+
 // Close closes the Vertex AI client
 func (e *VertexEmbedder) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	if e.client != nil {
 		return e.client.Close()
 	}
 	return nil
 }
 
+// isConnectionError reports whether err indicates a dead connection or expired
+// credentials, as opposed to a bad request that would fail again on a fresh client
+func isConnectionError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Unauthenticated:
+		return true
+	}
+	return false
+}
+
+// reconnect replaces failed with a freshly dialed client
+// If another request already replaced it, the existing replacement is reused
+func (e *VertexEmbedder) reconnect(ctx context.Context, failed *aiplatform.PredictionClient) (*aiplatform.PredictionClient, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.client != failed {
+		return e.client, nil
+	}
+
+	client, err := newPredictionClient(ctx, e.cfg)
+	if err != nil {
+		return nil, err
+	}
+	_ = failed.Close()
+	e.client = client
+	e.reconnects.Add(1)
+	if e.onReconnect != nil {
+		e.onReconnect()
+	}
+	log.Printf("Vertex AI prediction client reconnected (total reconnects: %d)", e.reconnects.Load())
+	return client, nil
+}
+
 // Embed generates an embedding for a single text
 func (e *VertexEmbedder) Embed(ctx context.Context, text string, taskType TaskType) ([]float64, error) {
 	embeddings, err := e.EmbedBatch(ctx, []string{text}, taskType)
@@ -107,7 +163,19 @@ func (e *VertexEmbedder) embedBatchInternal(ctx context.Context, texts []string,
 		Instances: instances,
 	}
 
-	resp, err := e.client.Predict(ctx, req)
+	e.mu.RLock()
+	client := e.client
+	e.mu.RUnlock()
+
+	resp, err := client.Predict(ctx, req)
+	if err != nil && isConnectionError(err) {
+		// Recreate the client once and retry before failing the request
+		client, reconnErr := e.reconnect(ctx, client)
+		if reconnErr != nil {
+			return nil, fmt.Errorf("vertex AI prediction failed: %w (reconnect failed: %v)", err, reconnErr)
+		}
+		resp, err = client.Predict(ctx, req)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("vertex AI prediction failed: %w", err)
 	}
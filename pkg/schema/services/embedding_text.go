@@ -0,0 +1,45 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/sola-scriptura-search-api/pkg/schema/config"
+)
+
+// embeddingTextTemplate is parsed once from EMBEDDING_TEXT_TEMPLATE
+var embeddingTextTemplate = sync.OnceValues(func() (*template.Template, error) {
+	tmpl, err := template.New("embedding_text").
+		Funcs(template.FuncMap{"join": strings.Join}).
+		Parse(config.GetConfig().EmbeddingTextTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parse EMBEDDING_TEXT_TEMPLATE: %w", err)
+	}
+	return tmpl, nil
+})
+
+// FormatEmbeddingText renders the document text embedded for a verse
+// Every path that embeds verses (enrichment, apply, consistency checks) must use this
+// so the whole index is embedded with one template. The default renders the bare verse
+// text when there are no themes, matching the base corpus, and appends
+// " [Themes: a, b]" for enriched verses. Changing the template requires re-embedding
+// the corpus: vectors built from different templates are not comparable, and mixing
+// them in one index degrades results.
+func FormatEmbeddingText(text string, themes []string) (string, error) {
+	tmpl, err := embeddingTextTemplate()
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	data := struct {
+		Text   string
+		Themes []string
+	}{Text: text, Themes: themes}
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("render embedding text: %w", err)
+	}
+	return sb.String(), nil
+}
@@ -3,14 +3,23 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"sync"
 
+	"github.com/sola-scriptura-search-api/pkg/cache"
 	"github.com/sola-scriptura-search-api/pkg/schema/config"
 )
 
 // EmbeddingsService handles text embedding operations using a pluggable backend
 type EmbeddingsService struct {
-	embedder Embedder
+	embedder   Embedder
+	queryCache *cache.LRU[string, []float64] // Query embeddings keyed by task type and normalized text
+	dimensions int                           // Expected embedding length
+	normalize  bool                          // L2-normalize every embedding
+
+	onCacheLookup func(hit bool) // Called on every query cache lookup, if set
 }
 
 var (
@@ -34,28 +43,144 @@ func GetEmbeddingsService() *EmbeddingsService {
 				initErr = fmt.Errorf("failed to create Vertex AI embedder: %w", err)
 				return
 			}
+		case "openai":
+			var err error
+			embedder, err = NewOpenAIEmbedder(cfg)
+			if err != nil {
+				initErr = fmt.Errorf("failed to create OpenAI embedder: %w", err)
+				return
+			}
 		default:
 			embedder = NewCustomEmbedder(cfg)
 		}
 
-		embeddingsService = &EmbeddingsService{
-			embedder: embedder,
-		}
+		embeddingsService = NewEmbeddingsService(embedder, cfg)
 	})
 	return embeddingsService
 }
 
+// NewEmbeddingsService creates an embeddings service over embedder with cfg's query
+// cache, dimensions and normalization; most callers want GetEmbeddingsService
+func NewEmbeddingsService(embedder Embedder, cfg *config.Config) *EmbeddingsService {
+	return &EmbeddingsService{
+		embedder:   embedder,
+		queryCache: cache.New[string, []float64](cfg.EmbeddingCacheSize, cfg.EmbeddingCacheTTL),
+		dimensions: cfg.EmbeddingDimensions,
+		normalize:  cfg.NormalizeEmbeddings,
+	}
+}
+
+// SetCacheLookupHook sets fn to be called with the result of every query cache lookup
+// Set it before the service is used; the API feeds it to Prometheus
+func (s *EmbeddingsService) SetCacheLookupHook(fn func(hit bool)) {
+	s.onCacheLookup = fn
+}
+
+// SetReconnectHook sets fn to be called each time the embedder recreates its client
+// Only the Vertex AI embedder reconnects; for others this is a no-op
+func (s *EmbeddingsService) SetReconnectHook(fn func()) {
+	if vertex, ok := s.embedder.(*VertexEmbedder); ok {
+		vertex.onReconnect = fn
+	}
+}
+
+// cacheLookup reports a query cache lookup to the hook, if one is set
+func (s *EmbeddingsService) cacheLookup(hit bool) {
+	if s.onCacheLookup != nil {
+		s.onCacheLookup(hit)
+	}
+}
+
 // GetInitError returns any error that occurred during initialization
 func GetInitError() error {
 	return initErr
 }
 
 // EmbedQuery embeds a query for retrieval
+// Whitespace is collapsed before embedding so trivially different queries share a
+// cache entry; repeated queries are served from the cache without calling the embedder.
 func (s *EmbeddingsService) EmbedQuery(ctx context.Context, query string) ([]float64, error) {
-	return s.embedder.Embed(ctx, query, TaskTypeQuery)
+	query = strings.Join(strings.Fields(query), " ")
+	key := string(TaskTypeQuery) + "\x00" + query
+	if cached, ok := s.queryCache.Get(key); ok {
+		s.cacheLookup(true)
+		return slices.Clone(cached), nil
+	}
+	s.cacheLookup(false)
+
+	embedding, err := s.embedder.Embed(ctx, query, TaskTypeQuery)
+	if err != nil {
+		return nil, err
+	}
+	s.maybeNormalize(embedding)
+	s.queryCache.Set(key, slices.Clone(embedding))
+	return embedding, nil
+}
+
+// healthCheckText is embedded by Check
+const healthCheckText = "test"
+
+// Check embeds a fixed string, bypassing the query cache, and verifies the embedding has
+// the configured dimensions. Returns the dimensions actually received.
+func (s *EmbeddingsService) Check(ctx context.Context) (int, error) {
+	embedding, err := s.embedder.Embed(ctx, healthCheckText, TaskTypeQuery)
+	if err != nil {
+		return 0, err
+	}
+	if len(embedding) != s.dimensions {
+		return len(embedding), fmt.Errorf("embedder returned %d-dimensional vectors but EMBEDDING_DIMENSIONS is %d", len(embedding), s.dimensions)
+	}
+	return len(embedding), nil
+}
+
+// Dimensions returns the expected embedding length
+func (s *EmbeddingsService) Dimensions() int {
+	return s.dimensions
 }
 
 // EmbedVerse embeds a verse as a document for retrieval
 func (s *EmbeddingsService) EmbedVerse(ctx context.Context, text string) ([]float64, error) {
-	return s.embedder.Embed(ctx, text, TaskTypeDocument)
+	embedding, err := s.embedder.Embed(ctx, text, TaskTypeDocument)
+	if err != nil {
+		return nil, err
+	}
+	s.maybeNormalize(embedding)
+	return embedding, nil
+}
+
+// EmbedVerses embeds verses as documents in batched calls, one embedding per text in order
+func (s *EmbeddingsService) EmbedVerses(ctx context.Context, texts []string) ([][]float64, error) {
+	embeddings, err := s.embedder.EmbedBatch(ctx, texts, TaskTypeDocument)
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) != len(texts) {
+		return nil, fmt.Errorf("embedded %d of %d texts", len(embeddings), len(texts))
+	}
+	for _, embedding := range embeddings {
+		s.maybeNormalize(embedding)
+	}
+	return embeddings, nil
+}
+
+// maybeNormalize scales embedding to unit length in place when normalization is enabled
+func (s *EmbeddingsService) maybeNormalize(embedding []float64) {
+	if s.normalize {
+		Normalize(embedding)
+	}
+}
+
+// Normalize scales v to unit L2 norm in place; a zero vector is left unchanged
+func Normalize(v []float64) {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	if sum == 0 {
+		return
+	}
+	norm := math.Sqrt(sum)
+	for i := range v {
+		v[i] /= norm
+	}
 }
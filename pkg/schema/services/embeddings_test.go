@@ -0,0 +1,30 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	v := []float64{3, 4, 12}
+	Normalize(v)
+
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	if norm := math.Sqrt(sum); math.Abs(norm-1) > 1e-9 {
+		t.Errorf("‖v‖ = %v after Normalize, want 1", norm)
+	}
+	if math.Abs(v[0]-3.0/13) > 1e-9 {
+		t.Errorf("v[0] = %v, want %v (direction preserved)", v[0], 3.0/13)
+	}
+
+	zero := []float64{0, 0, 0}
+	Normalize(zero)
+	for i, x := range zero {
+		if x != 0 || math.IsNaN(x) {
+			t.Errorf("zero[%d] = %v after Normalize, want 0", i, x)
+		}
+	}
+}
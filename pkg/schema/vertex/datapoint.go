@@ -0,0 +1,28 @@
+package vertex
+
+import (
+	aiplatformpb "cloud.google.com/go/aiplatform/apiv1/aiplatformpb"
+)
+
+// VerseDatapoint builds the index datapoint for a verse: book and testament restricts
+// for filtering, a book_order numeric restrict for book ranges, and the book as crowding
+// tag for per-book result caps
+// An upsert replaces the whole datapoint, so every writer must build it here or the
+// verse silently drops out of filtered searches
+func VerseDatapoint(verseID, book, testament string, bookOrder int, embedding []float32) *aiplatformpb.IndexDatapoint {
+	return &aiplatformpb.IndexDatapoint{
+		DatapointId:   verseID,
+		FeatureVector: embedding,
+		Restricts: []*aiplatformpb.IndexDatapoint_Restriction{
+			{Namespace: "book", AllowList: []string{book}},
+			{Namespace: "testament", AllowList: []string{testament}},
+		},
+		NumericRestricts: []*aiplatformpb.IndexDatapoint_NumericRestriction{
+			{
+				Namespace: "book_order",
+				Value:     &aiplatformpb.IndexDatapoint_NumericRestriction_ValueInt{ValueInt: int64(bookOrder)},
+			},
+		},
+		CrowdingTag: &aiplatformpb.IndexDatapoint_CrowdingTag{CrowdingAttribute: book},
+	}
+}
@@ -0,0 +1,29 @@
+package vertex
+
+import "testing"
+
+func TestVerseDatapointCarriesFilters(t *testing.T) {
+	dp := VerseDatapoint("Rom.8.28", "Rom", "NT", 45, []float32{0.1, 0.2})
+
+	if dp.DatapointId != "Rom.8.28" || len(dp.FeatureVector) != 2 {
+		t.Fatalf("datapoint = %v, want Rom.8.28 with its vector", dp)
+	}
+
+	restricts := make(map[string][]string)
+	for _, r := range dp.Restricts {
+		restricts[r.Namespace] = r.AllowList
+	}
+	for namespace, want := range map[string]string{"book": "Rom", "testament": "NT"} {
+		if got := restricts[namespace]; len(got) != 1 || got[0] != want {
+			t.Errorf("%s restrict = %v, want [%s]", namespace, got, want)
+		}
+	}
+
+	if len(dp.NumericRestricts) != 1 || dp.NumericRestricts[0].Namespace != "book_order" ||
+		dp.NumericRestricts[0].GetValueInt() != 45 {
+		t.Errorf("numeric restricts = %v, want book_order 45", dp.NumericRestricts)
+	}
+	if dp.CrowdingTag.GetCrowdingAttribute() != "Rom" {
+		t.Errorf("crowding tag = %v, want Rom", dp.CrowdingTag)
+	}
+}
@@ -0,0 +1,29 @@
+package vertex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseEmbedding parses a pgvector text representation like "[0.1,0.2,0.3]"
+// Exports parse 3072 dims for every verse, so this uses strconv rather than fmt.Sscanf
+func ParseEmbedding(text string) ([]float32, error) {
+	text = strings.TrimPrefix(text, "[")
+	text = strings.TrimSuffix(text, "]")
+
+	if text == "" {
+		return nil, fmt.Errorf("empty embedding")
+	}
+
+	parts := strings.Split(text, ",")
+	result := make([]float32, len(parts))
+	for i, p := range parts {
+		val, err := strconv.ParseFloat(strings.TrimSpace(p), 32)
+		if err != nil {
+			return nil, fmt.Errorf("parse float at position %d: %w", i, err)
+		}
+		result[i] = float32(val)
+	}
+	return result, nil
+}
@@ -0,0 +1,44 @@
+package vertex
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseEmbedding(t *testing.T) {
+	got, err := ParseEmbedding("[0.5, -1,2e-3]")
+	if err != nil {
+		t.Fatalf("ParseEmbedding: %v", err)
+	}
+	want := []float32{0.5, -1, 0.002}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("dim %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"[]", "[0.1,x]"} {
+		if _, err := ParseEmbedding(bad); err == nil {
+			t.Errorf("ParseEmbedding(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func BenchmarkParseEmbedding(b *testing.B) {
+	dims := make([]string, 3072)
+	for i := range dims {
+		dims[i] = strconv.FormatFloat(float64(i%97)/97-0.5, 'f', 8, 32)
+	}
+	text := "[" + strings.Join(dims, ",") + "]"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseEmbedding(text); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
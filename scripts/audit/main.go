@@ -0,0 +1,310 @@
+// audit_topic.go
+//
+// This script audits a curated topic against a canonical verse list: which verses
+// of each importance tier are mapped, which are missing, and which are mapped at
+// the wrong tier. It prints SQL that would bring the topic in line with the definition.
+//
+// Definitions are JSON files, one per topic:
+//   {
+//     "slug": "doctrine-of-the-trinity",
+//     "verses": [
+//       {"osis_verse_id": "Matt.28.19", "tier": 1},
+//       {"osis_verse_id": "2Cor.13.14", "tier": 2}
+//     ]
+//   }
+//
+// Usage:
+//   go run scripts/audit/main.go scripts/audit/definitions/trinity.json [more.json ...]
+//   go run scripts/audit/main.go -store scripts/audit/definitions/*.json
+//
+// Environment variables:
+//   POSTGRES_URI - PostgreSQL connection string
+//
+// Flags:
+//   -store  also replace each topic's rows in api.topic_canonical_verses with its
+//           definition, which GET /topics/:id/coverage reports against
+//
+// Topic mappings are never changed; review the emitted SQL and apply it by hand.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	"github.com/lib/pq"
+)
+
+// importanceTiers are the tiers reported, in order (1=essential, 2=important, 3=supporting)
+var importanceTiers = []int{1, 2, 3}
+
+var tierNames = map[int]string{1: "Essential", 2: "Important", 3: "Supporting"}
+
+// CanonicalVerse is one verse a topic definition expects to be mapped
+type CanonicalVerse struct {
+	VerseID string `json:"osis_verse_id"`
+	Tier    int    `json:"tier"`
+}
+
+// Definition is a topic definition file
+type Definition struct {
+	Slug   string           `json:"slug"`
+	Verses []CanonicalVerse `json:"verses"`
+}
+
+// AuditResult is the outcome of auditing one topic
+type AuditResult struct {
+	TopicID   int
+	TopicName string
+	Present   map[int][]CanonicalVerse // Mapped at the expected tier
+	Missing   map[int][]CanonicalVerse // Not mapped (or not in api.verses)
+	Mistiered []Mistiered              // Mapped, but at a different tier
+	NotInDB   map[string]bool          // Verse ids absent from api.verses
+}
+
+// Mistiered is a canonical verse mapped at a different tier than the definition
+type Mistiered struct {
+	CanonicalVerse
+	MappedTier int
+}
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	store := flag.Bool("store", false, "store each definition in api.topic_canonical_verses")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		return errors.New("usage: audit <definition.json> [more.json ...]")
+	}
+
+	godotenv.Load()
+
+	postgresURI := os.Getenv("POSTGRES_URI")
+	if postgresURI == "" {
+		return errors.New("POSTGRES_URI environment variable is required")
+	}
+
+	ctx := context.Background()
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", postgresURI)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	for _, path := range flag.Args() {
+		def, err := loadDefinition(path)
+		if err != nil {
+			return err
+		}
+		result, err := AuditTopic(ctx, db, def.Slug, def.Verses)
+		if err != nil {
+			return fmt.Errorf("audit %s: %w", def.Slug, err)
+		}
+		printReport(def.Slug, result)
+		printSQL(result)
+		if *store {
+			if err := StoreDefinition(ctx, db, result.TopicID, def.Verses); err != nil {
+				return fmt.Errorf("store %s: %w", def.Slug, err)
+			}
+			log.Printf("Stored %d canonical verses for %s", len(def.Verses), def.Slug)
+		}
+	}
+	return nil
+}
+
+// loadDefinition reads and validates a topic definition file
+func loadDefinition(path string) (*Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read definition: %w", err)
+	}
+
+	var def Definition
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("parse definition %s: %w", path, err)
+	}
+	if def.Slug == "" {
+		return nil, fmt.Errorf("definition %s: slug is required", path)
+	}
+	seen := make(map[string]bool, len(def.Verses))
+	for _, v := range def.Verses {
+		if _, ok := tierNames[v.Tier]; !ok {
+			return nil, fmt.Errorf("definition %s: %s has tier %d, expected 1-3", path, v.VerseID, v.Tier)
+		}
+		if seen[v.VerseID] {
+			return nil, fmt.Errorf("definition %s: %s is listed twice", path, v.VerseID)
+		}
+		seen[v.VerseID] = true
+	}
+	return &def, nil
+}
+
+// AuditTopic resolves the topic by slug and compares its mapped verses against canonical
+func AuditTopic(ctx context.Context, db *sqlx.DB, topicSlug string, canonical []CanonicalVerse) (*AuditResult, error) {
+	result := &AuditResult{
+		Present: make(map[int][]CanonicalVerse),
+		Missing: make(map[int][]CanonicalVerse),
+		NotInDB: make(map[string]bool),
+	}
+
+	err := db.QueryRowxContext(ctx, `SELECT id, name FROM api.topics WHERE slug = $1`, topicSlug).
+		Scan(&result.TopicID, &result.TopicName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no topic with slug %q", topicSlug)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find topic: %w", err)
+	}
+
+	ids := make([]string, len(canonical))
+	for i, v := range canonical {
+		ids[i] = v.VerseID
+	}
+
+	// One row per canonical verse: whether it exists in the corpus and its mapped tier
+	var rows []struct {
+		VerseID    string        `db:"osis_verse_id"`
+		InCorpus   bool          `db:"in_corpus"`
+		MappedTier sql.NullInt64 `db:"mapped_tier"`
+	}
+	if err := db.SelectContext(ctx, &rows, `
+		SELECT ids.osis_verse_id,
+		       v.id IS NOT NULL as in_corpus,
+		       CASE WHEN tv.verse_id IS NULL THEN NULL ELSE COALESCE(tv.importance_tier, 3) END as mapped_tier
+		FROM unnest($2::text[]) AS ids(osis_verse_id)
+		LEFT JOIN api.verses v ON v.osis_verse_id = ids.osis_verse_id
+		LEFT JOIN api.topic_verses tv ON tv.topic_id = $1 AND tv.verse_id = v.id
+	`, result.TopicID, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("compare topic verses: %w", err)
+	}
+
+	status := make(map[string]int, len(rows)) // 0 = unmapped, else mapped tier
+	for _, row := range rows {
+		if !row.InCorpus {
+			result.NotInDB[row.VerseID] = true
+		}
+		if row.MappedTier.Valid {
+			status[row.VerseID] = int(row.MappedTier.Int64)
+		}
+	}
+
+	for _, v := range canonical {
+		switch mapped := status[v.VerseID]; {
+		case mapped == 0:
+			result.Missing[v.Tier] = append(result.Missing[v.Tier], v)
+		case mapped == v.Tier:
+			result.Present[v.Tier] = append(result.Present[v.Tier], v)
+		default:
+			result.Mistiered = append(result.Mistiered, Mistiered{CanonicalVerse: v, MappedTier: mapped})
+		}
+	}
+	return result, nil
+}
+
+// StoreDefinition replaces a topic's canonical verses with verses in one transaction
+// Verses missing from api.verses are stored too, so coverage reports them as gaps
+func StoreDefinition(ctx context.Context, db *sqlx.DB, topicID int, verses []CanonicalVerse) error {
+	ids := make([]string, len(verses))
+	tiers := make([]int64, len(verses))
+	for i, v := range verses {
+		ids[i] = v.VerseID
+		tiers[i] = int64(v.Tier)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM api.topic_canonical_verses WHERE topic_id = $1`, topicID); err != nil {
+		return fmt.Errorf("clear canonical verses: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO api.topic_canonical_verses (topic_id, osis_verse_id, importance_tier)
+		SELECT $1, ids.osis_verse_id, ids.tier
+		FROM unnest($2::text[], $3::smallint[]) AS ids(osis_verse_id, tier)
+	`, topicID, pq.Array(ids), pq.Array(tiers)); err != nil {
+		return fmt.Errorf("insert canonical verses: %w", err)
+	}
+	return tx.Commit()
+}
+
+// printReport prints present vs missing counts and verse ids per tier
+func printReport(slug string, r *AuditResult) {
+	fmt.Println(strings.Repeat("=", 70))
+	fmt.Printf("TOPIC AUDIT: %s (%s, id %d)\n", r.TopicName, slug, r.TopicID)
+	fmt.Println(strings.Repeat("=", 70))
+
+	for _, tier := range importanceTiers {
+		present, missing := r.Present[tier], r.Missing[tier]
+		total := len(present) + len(missing)
+		for _, m := range r.Mistiered {
+			if m.Tier == tier {
+				total++
+			}
+		}
+		if total == 0 {
+			continue
+		}
+
+		fmt.Printf("\nTier %d (%s): %d/%d present\n", tier, tierNames[tier], len(present), total)
+		for _, v := range missing {
+			note := ""
+			if r.NotInDB[v.VerseID] {
+				note = " (not in api.verses)"
+			}
+			fmt.Printf("  MISSING  %s%s\n", v.VerseID, note)
+		}
+		for _, m := range r.Mistiered {
+			if m.Tier == tier {
+				fmt.Printf("  TIER     %s mapped at tier %d\n", m.VerseID, m.MappedTier)
+			}
+		}
+	}
+	fmt.Println()
+}
+
+// printSQL emits statements that add missing verses and correct tiers
+// Verses not in api.verses are skipped since there is nothing to map them to
+func printSQL(r *AuditResult) {
+	fmt.Printf("-- Fix-up SQL for topic %d (%s)\n", r.TopicID, r.TopicName)
+
+	statements := 0
+	for _, tier := range importanceTiers {
+		for _, v := range r.Missing[tier] {
+			if r.NotInDB[v.VerseID] {
+				fmt.Printf("-- skipped %s: not in api.verses\n", v.VerseID)
+				continue
+			}
+			fmt.Printf("INSERT INTO api.topic_verses (topic_id, verse_id, importance_tier)\n"+
+				"SELECT %d, id, %d FROM api.verses WHERE osis_verse_id = %s\n"+
+				"ON CONFLICT DO NOTHING;\n", r.TopicID, v.Tier, pq.QuoteLiteral(v.VerseID))
+			statements++
+		}
+	}
+	for _, m := range r.Mistiered {
+		fmt.Printf("UPDATE api.topic_verses SET importance_tier = %d\n"+
+			"WHERE topic_id = %d AND verse_id = (SELECT id FROM api.verses WHERE osis_verse_id = %s);\n",
+			m.Tier, r.TopicID, pq.QuoteLiteral(m.VerseID))
+		statements++
+	}
+	if statements == 0 {
+		fmt.Println("-- nothing to do")
+	}
+	fmt.Println()
+}
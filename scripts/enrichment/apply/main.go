@@ -3,14 +3,19 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 
 	aiplatform "cloud.google.com/go/aiplatform/apiv1"
 	aiplatformpb "cloud.google.com/go/aiplatform/apiv1/aiplatformpb"
+	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
+	"github.com/lib/pq"
+	"github.com/pgvector/pgvector-go"
 	pkgservices "github.com/sola-scriptura-search-api/pkg/schema/services"
+	"github.com/sola-scriptura-search-api/pkg/schema/vertex"
 	"google.golang.org/api/option"
 )
 
@@ -25,6 +30,15 @@ type EnrichmentResult struct {
 	AugmentedText    string   `json:"augmented_text"`
 }
 
+// defaultEnrichmentSource labels stored enrichment output when ENRICHMENT_SOURCE is unset
+const defaultEnrichmentSource = "gemini-3-flash-preview"
+
+// embedBatchSize is the most texts per embedding call (the Vertex AI limit)
+const embedBatchSize = 250
+
+// insertBatchSize bounds the rows sent per INSERT or UPDATE
+const insertBatchSize = 500
+
 func main() {
 	if err := run(); err != nil {
 		log.Fatal(err)
@@ -32,6 +46,12 @@ func main() {
 }
 
 func run() error {
+	storeEmbeddings := flag.Bool("store-embeddings", true,
+		"Write enriched embeddings to api.verses.augmented_embedding; pgvector searches them when PGVECTOR_USE_AUGMENTED_EMBEDDING is set")
+	overwriteEmbedding := flag.Bool("overwrite-embedding", false,
+		"Write enriched embeddings to api.verses.embedding instead, replacing the base embedding")
+	flag.Parse()
+
 	godotenv.Load()
 	ctx := context.Background()
 
@@ -47,6 +67,21 @@ func run() error {
 	}
 	log.Printf("Loaded %d enrichment results\n", len(results))
 
+	// Persist the structured output before embedding so it survives embed failures
+	db, err := sqlx.Connect("postgres", os.Getenv("POSTGRES_URI"))
+	if err != nil {
+		return fmt.Errorf("connect to postgres: %w", err)
+	}
+	defer db.Close()
+
+	source := os.Getenv("ENRICHMENT_SOURCE")
+	if source == "" {
+		source = defaultEnrichmentSource
+	}
+	if err := storeEnrichment(ctx, db, results, source); err != nil {
+		return err
+	}
+
 	// Get embeddings service (uses existing config)
 	embeddingSvc := pkgservices.GetEmbeddingsService()
 	if err := pkgservices.GetInitError(); err != nil {
@@ -78,32 +113,71 @@ func run() error {
 
 	indexName := fmt.Sprintf("projects/%s/locations/%s/indexes/%s", projectID, location, indexID)
 
-	// Process each result - generate embeddings
-	var datapoints []*aiplatformpb.IndexDatapoint
+	// Render every verse's text up front so it can be embedded in batches
+	texts := make([]string, len(results))
 	for i, result := range results {
-		log.Printf("[%d/%d] Embedding %s...\n", i+1, len(results), result.Verse.VerseID)
-
-		// Generate embedding for augmented text using existing service
-		embedding, err := embeddingSvc.EmbedVerse(ctx, result.AugmentedText)
+		// Re-render the augmented text with the current template so the index is
+		// embedded uniformly even if results were generated under an older template
+		text, err := pkgservices.FormatEmbeddingText(result.Verse.Text, result.TheoAnnotations)
 		if err != nil {
-			log.Printf("  Warning: failed to embed %s: %v\n", result.Verse.VerseID, err)
-			continue
+			return fmt.Errorf("format embedding text: %w", err)
 		}
+		texts[i] = text
+	}
 
-		// Convert []float64 to []float32 for Vertex AI
-		embedding32 := make([]float32, len(embedding))
-		for j, v := range embedding {
-			embedding32[j] = float32(v)
-		}
+	// Datapoints carry the same restricts and crowding tag as scripts/upsert; an upsert
+	// replaces the whole datapoint, so omitting them would drop verses from filtered searches
+	books, err := loadVerseBooks(ctx, db, results)
+	if err != nil {
+		return err
+	}
+
+	// Embed in batches, mapping each embedding back to its verse by position
+	var datapoints []*aiplatformpb.IndexDatapoint
+	var verseIDs, vectors []string // pgvector text form for Postgres
+	for i := 0; i < len(results); i += embedBatchSize {
+		end := min(i+embedBatchSize, len(results))
+		log.Printf("[%d-%d/%d] Embedding batch...\n", i+1, end, len(results))
+
+		embeddings := embedBatch(ctx, embeddingSvc, results[i:end], texts[i:end])
+		for j, embedding := range embeddings {
+			if len(embedding) == 0 {
+				continue
+			}
 
-		// Create datapoint
-		datapoint := &aiplatformpb.IndexDatapoint{
-			DatapointId:   result.Verse.VerseID,
-			FeatureVector: embedding32,
+			// Convert []float64 to []float32 for Vertex AI
+			embedding32 := make([]float32, len(embedding))
+			for k, v := range embedding {
+				embedding32[k] = float32(v)
+			}
+
+			verseID := results[i+j].Verse.VerseID
+			book, ok := books[verseID]
+			if !ok {
+				log.Printf("  Warning: skipping %s: not in api.verses\n", verseID)
+				continue
+			}
+			datapoints = append(datapoints, vertex.VerseDatapoint(verseID, book.Book, book.Testament, book.BookOrder, embedding32))
+			verseIDs = append(verseIDs, verseID)
+			vectors = append(vectors, pgvector.NewVector(embedding32).String())
 		}
-		datapoints = append(datapoints, datapoint)
+	}
+	log.Printf("Embedded %d of %d verses\n", len(datapoints), len(results))
 
-		log.Printf("  Embedded: %d dimensions\n", len(embedding))
+	// Store the same vectors in Postgres so pgvector and Vertex AI stay comparable
+	if *storeEmbeddings || *overwriteEmbedding {
+		column := "augmented_embedding"
+		if *overwriteEmbedding {
+			column = "embedding"
+		}
+		if err := storeEmbeddingColumn(ctx, db, column, verseIDs, vectors); err != nil {
+			return err
+		}
+		// pgvector searches the view, which carries both columns
+		if _, err := db.ExecContext(ctx, `REFRESH MATERIALIZED VIEW api_views.mv_verses_search`); err != nil {
+			return fmt.Errorf("refresh mv_verses_search: %w", err)
+		}
+		log.Println("Refreshed api_views.mv_verses_search")
 	}
 
 	// Upsert all datapoints
@@ -133,3 +207,157 @@ func run() error {
 	log.Println("Done! Enriched embeddings uploaded to Vertex AI.")
 	return nil
 }
+
+// storeEnrichment inserts every result's synthetic queries and theological annotations
+// in one transaction, skipping rows already stored and verses missing from api.verses
+func storeEnrichment(ctx context.Context, db *sqlx.DB, results []EnrichmentResult, source string) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queries, err := insertVerseValues(ctx, tx, "api.verse_synthetic_queries", "query", results,
+		func(r EnrichmentResult) []string { return r.SyntheticQueries }, source)
+	if err != nil {
+		return fmt.Errorf("store synthetic queries: %w", err)
+	}
+	annotations, err := insertVerseValues(ctx, tx, "api.verse_annotations", "annotation", results,
+		func(r EnrichmentResult) []string { return r.TheoAnnotations }, source)
+	if err != nil {
+		return fmt.Errorf("store annotations: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	log.Printf("Stored %d synthetic queries and %d annotations (the rest already stored or for unknown verses)\n",
+		queries, annotations)
+	return nil
+}
+
+// insertVerseValues batch-inserts (verse, value, source) rows into table, where column
+// holds the value; returns the number of rows inserted
+func insertVerseValues(ctx context.Context, tx *sqlx.Tx, table, column string, results []EnrichmentResult, values func(EnrichmentResult) []string, source string) (int64, error) {
+	var verseIDs, texts []string
+	for _, result := range results {
+		for _, v := range values(result) {
+			verseIDs = append(verseIDs, result.Verse.VerseID)
+			texts = append(texts, v)
+		}
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (verse_id, %s, source)
+		SELECT v.id, q.value, $3
+		FROM unnest($1::text[], $2::text[]) AS q(osis_verse_id, value)
+		JOIN api.verses v ON v.osis_verse_id = q.osis_verse_id
+		ON CONFLICT DO NOTHING
+	`, table, column)
+
+	var stored int64
+	for i := 0; i < len(texts); i += insertBatchSize {
+		end := min(i+insertBatchSize, len(texts))
+		res, err := tx.ExecContext(ctx, query, pq.Array(verseIDs[i:end]), pq.Array(texts[i:end]), source)
+		if err != nil {
+			return 0, fmt.Errorf("insert batch %d-%d: %w", i, end, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("count inserted rows: %w", err)
+		}
+		stored += n
+	}
+	return stored, nil
+}
+
+// verseBook is the book a verse belongs to, as needed for its index datapoint
+type verseBook struct {
+	VerseID   string `db:"osis_verse_id"`
+	Book      string `db:"book"`
+	Testament string `db:"testament"`
+	BookOrder int    `db:"book_order"`
+}
+
+// loadVerseBooks looks up the book of every result's verse, keyed by OSIS id
+// Verses missing from api.verses are omitted
+func loadVerseBooks(ctx context.Context, db *sqlx.DB, results []EnrichmentResult) (map[string]verseBook, error) {
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.Verse.VerseID
+	}
+
+	var rows []verseBook
+	if err := db.SelectContext(ctx, &rows, `
+		SELECT v.osis_verse_id, b.osis_id AS book, b.testament, b.book_order
+		FROM api.verses v
+		JOIN api.books b ON v.book_id = b.id
+		WHERE v.osis_verse_id = ANY($1)
+	`, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("load verse books: %w", err)
+	}
+
+	books := make(map[string]verseBook, len(rows))
+	for _, row := range rows {
+		books[row.VerseID] = row
+	}
+	return books, nil
+}
+
+// storeEmbeddingColumn writes each verse's vector to column of api.verses in one
+// transaction; verses missing from api.verses are skipped
+func storeEmbeddingColumn(ctx context.Context, db *sqlx.DB, column string, verseIDs, vectors []string) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`
+		UPDATE api.verses v
+		SET %s = q.embedding::vector
+		FROM unnest($1::text[], $2::text[]) AS q(osis_verse_id, embedding)
+		WHERE v.osis_verse_id = q.osis_verse_id
+	`, column)
+
+	var stored int64
+	for i := 0; i < len(vectors); i += insertBatchSize {
+		end := min(i+insertBatchSize, len(vectors))
+		res, err := tx.ExecContext(ctx, query, pq.Array(verseIDs[i:end]), pq.Array(vectors[i:end]))
+		if err != nil {
+			return fmt.Errorf("store %s batch %d-%d: %w", column, i, end, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("count updated rows: %w", err)
+		}
+		stored += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	log.Printf("Stored %d of %d embeddings in api.verses.%s\n", stored, len(vectors), column)
+	return nil
+}
+
+// embedBatch embeds one batch of verses, returning nil for each verse that failed
+// If the batch call fails, each verse is retried alone so one bad text only skips itself
+func embedBatch(ctx context.Context, svc *pkgservices.EmbeddingsService, results []EnrichmentResult, texts []string) [][]float64 {
+	embeddings, err := svc.EmbedVerses(ctx, texts)
+	if err == nil {
+		return embeddings
+	}
+	log.Printf("  Warning: batch embedding failed, retrying per verse: %v\n", err)
+
+	embeddings = make([][]float64, len(texts))
+	for i, text := range texts {
+		embedding, err := svc.EmbedVerse(ctx, text)
+		if err != nil {
+			log.Printf("  Warning: failed to embed %s: %v\n", results[i].Verse.VerseID, err)
+			continue
+		}
+		embeddings[i] = embedding
+	}
+	return embeddings
+}
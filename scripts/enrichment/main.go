@@ -3,15 +3,19 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strings"
 
 	"cloud.google.com/go/vertexai/genai"
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
 	"github.com/lib/pq"
+	pkgservices "github.com/sola-scriptura-search-api/pkg/schema/services"
 )
 
 // Verse represents a verse with its context
@@ -21,6 +25,7 @@ type Verse struct {
 	Chapter       int      `db:"chapter" json:"chapter"`
 	VerseNum      int      `db:"verse" json:"verse"`
 	Text          string   `db:"text" json:"text"`
+	Genre         string   `db:"genre" json:"genre,omitempty"`
 	CrossRefs     []string `json:"cross_refs,omitempty"`
 	Topics        []string `json:"topics,omitempty"`
 	ChapterText   string   `json:"chapter_context,omitempty"`
@@ -35,13 +40,14 @@ type EnrichmentResult struct {
 }
 
 // SampleConfig defines the sampling strategy
+// Load one from JSON with -config; fields missing from the file keep their defaults
 type SampleConfig struct {
 	// Specific theologically significant verses to always include
-	MustInclude []string
+	MustInclude []string `json:"must_include"`
 	// Number of random verses per testament
-	RandomPerTestament int
-	// Genres to sample from
-	Genres []string
+	RandomPerTestament int `json:"random_per_testament"`
+	// Genres to sample from (api.books.genre, e.g. "wisdom", "epistles"); empty = all
+	Genres []string `json:"genres"`
 }
 
 var defaultSampleConfig = SampleConfig{
@@ -64,6 +70,13 @@ var defaultSampleConfig = SampleConfig{
 	RandomPerTestament: 15,
 }
 
+const (
+	// resultsFile doubles as the checkpoint: it is rewritten after every enriched verse
+	resultsFile = "enrichment_results.json"
+	// sampleFile records the selected verses so a resumed run enriches the same sample
+	sampleFile = "enrichment_sample.json"
+)
+
 func main() {
 	if err := run(); err != nil {
 		log.Fatal(err)
@@ -71,6 +84,28 @@ func main() {
 }
 
 func run() error {
+	fresh := flag.Bool("fresh", false, "Ignore any checkpoint and start a new sample from scratch")
+	configFile := flag.String("config", "", "JSON file with a SampleConfig (must_include, random_per_testament, genres)")
+	randomPerTestament := flag.Int("random-per-testament", -1, "Random verses per testament (overrides the config)")
+	genres := flag.String("genres", "", "Comma-separated genres to sample random verses from (overrides the config)")
+	flag.Parse()
+
+	sampleConfig, err := loadSampleConfig(*configFile)
+	if err != nil {
+		return err
+	}
+	if *randomPerTestament >= 0 {
+		sampleConfig.RandomPerTestament = *randomPerTestament
+	}
+	if *genres != "" {
+		sampleConfig.Genres = nil
+		for _, g := range strings.Split(*genres, ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				sampleConfig.Genres = append(sampleConfig.Genres, g)
+			}
+		}
+	}
+
 	godotenv.Load()
 
 	ctx := context.Background()
@@ -82,6 +117,10 @@ func run() error {
 	}
 	defer db.Close()
 
+	if err := validateGenres(ctx, db, sampleConfig.Genres); err != nil {
+		return err
+	}
+
 	// Initialize Vertex AI Gemini client (uses ADC)
 	projectID := os.Getenv("GCP_PROJECT_ID")
 	location := os.Getenv("GEMINI_LOCATION")
@@ -97,17 +136,43 @@ func run() error {
 	}
 	defer client.Close()
 
-	// Get sample verses
-	log.Println("Selecting sample verses...")
-	verses, err := getSampleVerses(ctx, db, defaultSampleConfig)
-	if err != nil {
-		return fmt.Errorf("get sample verses: %w", err)
+	// Resume from the checkpoint unless asked to start over
+	var verses []Verse
+	var results []EnrichmentResult
+	if !*fresh {
+		if verses, err = readJSONFile[[]Verse](sampleFile); err != nil {
+			return fmt.Errorf("read sample: %w", err)
+		}
+		if results, err = readJSONFile[[]EnrichmentResult](resultsFile); err != nil {
+			return fmt.Errorf("read checkpoint: %w", err)
+		}
+	}
+
+	if verses == nil {
+		log.Println("Selecting sample verses...")
+		verses, err = getSampleVerses(ctx, db, sampleConfig)
+		if err != nil {
+			return fmt.Errorf("get sample verses: %w", err)
+		}
+		if err := writeJSONFile(verses, sampleFile); err != nil {
+			return fmt.Errorf("write sample: %w", err)
+		}
+		log.Printf("Selected %d verses for enrichment\n", len(verses))
+	} else {
+		// The saved sample wins over -config and sampling flags until -fresh
+		log.Printf("Resuming: %d of %d sampled verses already enriched (use -fresh to start over)\n", len(results), len(verses))
 	}
-	log.Printf("Selected %d verses for enrichment\n", len(verses))
 
-	// Enrich each verse
-	results := make([]EnrichmentResult, 0, len(verses))
+	done := make(map[string]bool, len(results))
+	for _, r := range results {
+		done[r.Verse.VerseID] = true
+	}
+
+	// Enrich each verse not already in the checkpoint
 	for i, verse := range verses {
+		if done[verse.VerseID] {
+			continue
+		}
 		log.Printf("[%d/%d] Enriching %s...\n", i+1, len(verses), verse.VerseID)
 
 		result, err := enrichVerse(ctx, client, verse)
@@ -116,18 +181,16 @@ func run() error {
 			continue
 		}
 		results = append(results, result)
+		if err := writeJSONFile(results, resultsFile); err != nil {
+			return fmt.Errorf("write checkpoint: %w", err)
+		}
 
 		// Log a preview
 		log.Printf("  Annotations: %v\n", result.TheoAnnotations[:min(3, len(result.TheoAnnotations))])
 		log.Printf("  Queries: %v\n", result.SyntheticQueries[:min(2, len(result.SyntheticQueries))])
 	}
 
-	// Write results to JSON file
-	outputFile := "enrichment_results.json"
-	if err := writeResults(results, outputFile); err != nil {
-		return fmt.Errorf("write results: %w", err)
-	}
-	log.Printf("Results written to %s\n", outputFile)
+	log.Printf("Results written to %s\n", resultsFile)
 
 	// Also write a human-readable summary
 	summaryFile := "enrichment_summary.md"
@@ -139,13 +202,54 @@ func run() error {
 	return nil
 }
 
+// loadSampleConfig returns defaultSampleConfig overlaid with the JSON file at path, if any
+func loadSampleConfig(path string) (SampleConfig, error) {
+	config := defaultSampleConfig
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("read sample config: %w", err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("parse sample config %s: %w", path, err)
+	}
+	if config.RandomPerTestament < 0 {
+		return config, fmt.Errorf("sample config %s: random_per_testament must be at least 0", path)
+	}
+	return config, nil
+}
+
+// validateGenres checks every requested genre is used by at least one book
+func validateGenres(ctx context.Context, db *sqlx.DB, genres []string) error {
+	if len(genres) == 0 {
+		return nil
+	}
+
+	var known []string
+	if err := db.SelectContext(ctx, &known, `
+		SELECT DISTINCT genre FROM api.books WHERE genre IS NOT NULL ORDER BY genre
+	`); err != nil {
+		return fmt.Errorf("list genres: %w", err)
+	}
+
+	for _, g := range genres {
+		if !slices.Contains(known, g) {
+			return fmt.Errorf("unknown genre %q (known: %s)", g, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
 func getSampleVerses(ctx context.Context, db *sqlx.DB, config SampleConfig) ([]Verse, error) {
 	verses := make([]Verse, 0, len(config.MustInclude)+config.RandomPerTestament*2)
 
 	// Get must-include verses
 	if len(config.MustInclude) > 0 {
 		query := `
-			SELECT v.osis_verse_id, b.osis_id as book, v.chapter, v.verse, v.text
+			SELECT v.osis_verse_id, b.osis_id as book, v.chapter, v.verse, v.text, COALESCE(b.genre, '') as genre
 			FROM api.verses v
 			JOIN api.books b ON v.book_id = b.id
 			WHERE v.osis_verse_id = ANY($1)
@@ -157,34 +261,37 @@ func getSampleVerses(ctx context.Context, db *sqlx.DB, config SampleConfig) ([]V
 		verses = append(verses, mustInclude...)
 	}
 
-	// Get random OT verses
+	// Get random OT verses, only from config.Genres when set (a testament with none
+	// of the genres, e.g. NT for "wisdom", contributes no random verses)
 	queryOT := `
-		SELECT v.osis_verse_id, b.osis_id as book, v.chapter, v.verse, v.text
+		SELECT v.osis_verse_id, b.osis_id as book, v.chapter, v.verse, v.text, COALESCE(b.genre, '') as genre
 		FROM api.verses v
 		JOIN api.books b ON v.book_id = b.id
 		WHERE b.testament = 'OT'
 		AND v.osis_verse_id != ALL($1)
+		AND (COALESCE(cardinality($3::text[]), 0) = 0 OR b.genre = ANY($3))
 		ORDER BY RANDOM()
 		LIMIT $2
 	`
 	var otVerses []Verse
-	if err := db.SelectContext(ctx, &otVerses, queryOT, pq.Array(config.MustInclude), config.RandomPerTestament); err != nil {
+	if err := db.SelectContext(ctx, &otVerses, queryOT, pq.Array(config.MustInclude), config.RandomPerTestament, pq.Array(config.Genres)); err != nil {
 		return nil, fmt.Errorf("get OT verses: %w", err)
 	}
 	verses = append(verses, otVerses...)
 
 	// Get random NT verses
 	queryNT := `
-		SELECT v.osis_verse_id, b.osis_id as book, v.chapter, v.verse, v.text
+		SELECT v.osis_verse_id, b.osis_id as book, v.chapter, v.verse, v.text, COALESCE(b.genre, '') as genre
 		FROM api.verses v
 		JOIN api.books b ON v.book_id = b.id
 		WHERE b.testament = 'NT'
 		AND v.osis_verse_id != ALL($1)
+		AND (COALESCE(cardinality($3::text[]), 0) = 0 OR b.genre = ANY($3))
 		ORDER BY RANDOM()
 		LIMIT $2
 	`
 	var ntVerses []Verse
-	if err := db.SelectContext(ctx, &ntVerses, queryNT, pq.Array(config.MustInclude), config.RandomPerTestament); err != nil {
+	if err := db.SelectContext(ctx, &ntVerses, queryNT, pq.Array(config.MustInclude), config.RandomPerTestament, pq.Array(config.Genres)); err != nil {
 		return nil, fmt.Errorf("get NT verses: %w", err)
 	}
 	verses = append(verses, ntVerses...)
@@ -266,8 +373,12 @@ func enrichVerse(ctx context.Context, client *genai.Client, verse Verse) (Enrich
 	}
 	result.SyntheticQueries = queries
 
-	// Build augmented text for Option 1
-	result.AugmentedText = fmt.Sprintf("%s [Themes: %s]", verse.Text, strings.Join(annotations, ", "))
+	// Build augmented text for Option 1 using the shared embedding template
+	augmented, err := pkgservices.FormatEmbeddingText(verse.Text, annotations)
+	if err != nil {
+		return result, fmt.Errorf("format augmented text: %w", err)
+	}
+	result.AugmentedText = augmented
 
 	return result, nil
 }
@@ -385,12 +496,34 @@ func parseJSONArray(text string) ([]string, error) {
 	return result, nil
 }
 
-func writeResults(results []EnrichmentResult, filename string) error {
-	data, err := json.MarshalIndent(results, "", "  ")
+// writeJSONFile writes v as indented JSON, replacing filename atomically so an
+// interrupted run never leaves a truncated checkpoint behind
+func writeJSONFile(v any, filename string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filename, data, 0644)
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// readJSONFile reads a file written by writeJSONFile, returning the zero value if it does not exist
+func readJSONFile[T any](filename string) (T, error) {
+	var v T
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return v, nil
+	}
+	if err != nil {
+		return v, err
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("parse %s: %w", filename, err)
+	}
+	return v, nil
 }
 
 func writeSummary(results []EnrichmentResult, filename string) error {
@@ -403,6 +536,9 @@ func writeSummary(results []EnrichmentResult, filename string) error {
 	for _, r := range results {
 		sb.WriteString(fmt.Sprintf("## %s\n\n", r.Verse.VerseID))
 		sb.WriteString(fmt.Sprintf("**Text:** %s\n\n", r.Verse.Text))
+		if r.Verse.Genre != "" {
+			sb.WriteString(fmt.Sprintf("**Genre:** %s\n\n", r.Verse.Genre))
+		}
 
 		sb.WriteString("**Theological Annotations:**\n")
 		for _, a := range r.TheoAnnotations {
@@ -1,13 +1,19 @@
 // export_embeddings.go
 //
 // This script exports verse embeddings from PostgreSQL to a JSONL file
-// formatted for Vertex AI Vector Search.
+// formatted for Vertex AI Vector Search, or to CSV for other tools.
 //
 // Usage:
 //   go run scripts/export_embeddings.go -output embeddings.jsonl
+//   go run scripts/export_embeddings.go -format csv -output embeddings.csv
 //
-// The output format is one JSON object per line:
-//   {"id": "John.3.16", "embedding": [0.1, 0.2, ...], "restricts": [{"namespace": "book", "allow": ["John"]}]}
+// The CSV format has a header row and the columns id,book,chapter,verse,embedding,
+// where embedding is the pgvector text form quoted as one field:
+//   id,book,chapter,verse,embedding
+//   John.3.16,John,3,16,"[0.1,0.2,...]"
+//
+// The JSONL format (default) is one JSON object per line:
+//   {"id": "John.3.16", "embedding": [0.1, 0.2, ...], "restricts": [{"namespace": "book", "allow": ["John"]}, {"namespace": "testament", "allow": ["NT"]}], "numeric_restricts": [{"namespace": "book_order", "value_int": 43}], "crowding_tag": {"crowding_attribute": "John"}}
 //
 // After running this script:
 // 1. Upload the file to Cloud Storage:
@@ -19,23 +25,26 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"flag"
-	"fmt"
 	"log"
 	"os"
-	"strings"
+	"strconv"
 
 	"github.com/joho/godotenv"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/sola-scriptura-search-api/pkg/schema/vertex"
 )
 
 // DataPoint represents a single embedding for Vertex AI Vector Search
 type DataPoint struct {
-	ID        string     `json:"id"`
-	Embedding []float32  `json:"embedding"`
-	Restricts []Restrict `json:"restricts,omitempty"`
+	ID               string            `json:"id"`
+	Embedding        []float32         `json:"embedding"`
+	Restricts        []Restrict        `json:"restricts,omitempty"`
+	NumericRestricts []NumericRestrict `json:"numeric_restricts,omitempty"`
+	CrowdingTag      *CrowdingTag      `json:"crowding_tag,omitempty"`
 }
 
 // Restrict defines a token-based filter
@@ -44,10 +53,29 @@ type Restrict struct {
 	Allow     []string `json:"allow"`
 }
 
+// CrowdingTag groups datapoints so queries can cap neighbors per group
+type CrowdingTag struct {
+	CrowdingAttribute string `json:"crowding_attribute"`
+}
+
+// NumericRestrict defines a numeric filter, queried with range comparisons
+type NumericRestrict struct {
+	Namespace string `json:"namespace"`
+	ValueInt  int64  `json:"value_int"`
+}
+
 func main() {
-	outputFile := flag.String("output", "embeddings.jsonl", "Output JSONL file path")
+	outputFile := flag.String("output", "", "Output file path (default embeddings.<format>)")
+	format := flag.String("format", "jsonl", "Output format: jsonl (Vertex AI) or csv")
 	flag.Parse()
 
+	if *format != "jsonl" && *format != "csv" {
+		log.Fatalf("Unknown format %q: expected jsonl or csv", *format)
+	}
+	if *outputFile == "" {
+		*outputFile = "embeddings." + *format
+	}
+
 	// Load environment variables
 	godotenv.Load()
 
@@ -87,18 +115,29 @@ func main() {
 	log.Printf("Processing %d books...\n", len(books))
 
 	encoder := json.NewEncoder(f)
+	csvWriter := csv.NewWriter(f)
+	if *format == "csv" {
+		if err := csvWriter.Write([]string{"id", "book", "chapter", "verse", "embedding"}); err != nil {
+			log.Fatalf("Failed to write CSV header: %v", err)
+		}
+	}
 	count := 0
 
 	// Process one book at a time to avoid temp file limits
 	for _, book := range books {
 		rows, err := db.QueryxContext(ctx, `
 			SELECT
-				verse_id,
-				book,
-				embedding::text as embedding_text
-			FROM api_views.mv_verses_search
-			WHERE embedding IS NOT NULL AND book = $1
-			ORDER BY chapter, verse
+				mv.verse_id,
+				mv.book,
+				b.testament,
+				b.book_order,
+				mv.chapter,
+				mv.verse,
+				mv.embedding::text as embedding_text
+			FROM api_views.mv_verses_search mv
+			JOIN api.books b ON b.osis_id = mv.book
+			WHERE mv.embedding IS NOT NULL AND mv.book = $1
+			ORDER BY mv.chapter, mv.verse
 		`, book)
 		if err != nil {
 			log.Fatalf("Failed to query verses for book %s: %v", book, err)
@@ -106,20 +145,34 @@ func main() {
 
 		bookCount := 0
 		for rows.Next() {
-			var verseID, bookName, embeddingText string
-			if err := rows.Scan(&verseID, &bookName, &embeddingText); err != nil {
+			var verseID, bookName, testament, embeddingText string
+			var bookOrder int64
+			var chapter, verse int
+			if err := rows.Scan(&verseID, &bookName, &testament, &bookOrder, &chapter, &verse, &embeddingText); err != nil {
 				rows.Close()
 				log.Fatalf("Failed to scan row: %v", err)
 			}
 
+			if *format == "csv" {
+				// The pgvector text form is already bracketed; csv quotes it for the commas
+				if err := csvWriter.Write([]string{verseID, bookName, strconv.Itoa(chapter), strconv.Itoa(verse), embeddingText}); err != nil {
+					rows.Close()
+					log.Fatalf("Failed to write CSV row: %v", err)
+				}
+				count++
+				bookCount++
+				continue
+			}
+
 			// Parse the embedding from pgvector text format: "[0.1,0.2,...]"
-			embedding, err := parseEmbedding(embeddingText)
+			embedding, err := vertex.ParseEmbedding(embeddingText)
 			if err != nil {
 				log.Printf("Warning: failed to parse embedding for %s: %v", verseID, err)
 				continue
 			}
 
-			// Create the data point with book as a filter
+			// Create the data point with book and testament as filters, book_order for ranges
+			// and the book as crowding tag for per-book result caps
 			dp := DataPoint{
 				ID:        verseID,
 				Embedding: embedding,
@@ -128,7 +181,18 @@ func main() {
 						Namespace: "book",
 						Allow:     []string{bookName},
 					},
+					{
+						Namespace: "testament",
+						Allow:     []string{testament},
+					},
 				},
+				NumericRestricts: []NumericRestrict{
+					{
+						Namespace: "book_order",
+						ValueInt:  bookOrder,
+					},
+				},
+				CrowdingTag: &CrowdingTag{CrowdingAttribute: bookName},
 			}
 
 			if err := encoder.Encode(dp); err != nil {
@@ -146,37 +210,21 @@ func main() {
 		}
 		rows.Close()
 
+		// Flush per book so buffered CSV stays bounded like the row stream
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			log.Fatalf("Failed to write CSV: %v", err)
+		}
+
 		log.Printf("  %s: %d verses", book, bookCount)
 	}
 
 	log.Printf("Successfully exported %d embeddings to %s\n", count, *outputFile)
+	if *format == "csv" {
+		return
+	}
 	log.Println("\nNext steps:")
 	log.Println("1. Upload to Cloud Storage:")
 	log.Printf("   gsutil cp %s gs://YOUR_BUCKET/embeddings/\n", *outputFile)
 	log.Println("\n2. Create Vertex AI index (see scripts/setup_vertex_index.go)")
 }
-
-// parseEmbedding parses a pgvector text representation like "[0.1,0.2,0.3]"
-func parseEmbedding(text string) ([]float32, error) {
-	// Remove brackets
-	text = strings.TrimPrefix(text, "[")
-	text = strings.TrimSuffix(text, "]")
-
-	if text == "" {
-		return nil, fmt.Errorf("empty embedding")
-	}
-
-	parts := strings.Split(text, ",")
-	result := make([]float32, len(parts))
-
-	for i, p := range parts {
-		var val float32
-		_, err := fmt.Sscanf(strings.TrimSpace(p), "%f", &val)
-		if err != nil {
-			return nil, fmt.Errorf("parse float at position %d: %w", i, err)
-		}
-		result[i] = val
-	}
-
-	return result, nil
-}
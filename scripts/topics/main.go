@@ -0,0 +1,118 @@
+// delete_topic.go
+//
+// This script removes a curated topic and its verse mappings so a topic that was
+// inserted wrongly can be dropped and re-inserted cleanly.
+//
+// The topic and its api.topic_verses rows are deleted in one transaction, then
+// api_views.mv_topics_summary is refreshed so the API stops listing the topic.
+//
+// Usage:
+//   go run scripts/topics/main.go -delete -slug doctrine-of-the-trinity
+//
+// Environment variables:
+//   POSTGRES_URI - PostgreSQL connection string
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	deleteTopic := flag.Bool("delete", false, "Delete the topic and its verse mappings")
+	slug := flag.String("slug", "", "Slug of the topic to delete")
+	flag.Parse()
+
+	if !*deleteTopic {
+		return errors.New("nothing to do: pass -delete")
+	}
+	if *slug == "" {
+		return errors.New("-slug is required")
+	}
+
+	godotenv.Load()
+
+	postgresURI := os.Getenv("POSTGRES_URI")
+	if postgresURI == "" {
+		return errors.New("POSTGRES_URI environment variable is required")
+	}
+
+	ctx := context.Background()
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", postgresURI)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	name, mappings, err := deleteBySlug(ctx, db, *slug)
+	if err != nil {
+		return err
+	}
+	log.Printf("Deleted topic %q (%s) and %d verse mappings\n", name, *slug, mappings)
+
+	// Refresh after commit; the deletion stands even if the refresh fails
+	if _, err := db.ExecContext(ctx, `REFRESH MATERIALIZED VIEW api_views.mv_topics_summary`); err != nil {
+		return fmt.Errorf("refresh mv_topics_summary: %w", err)
+	}
+	log.Println("Refreshed api_views.mv_topics_summary")
+	return nil
+}
+
+// deleteBySlug removes the topic and its api.topic_verses rows in one transaction,
+// returning the topic name and the number of mappings removed
+func deleteBySlug(ctx context.Context, db *sqlx.DB, slug string) (string, int64, error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return "", 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var name string
+	err = tx.QueryRowxContext(ctx, `
+		SELECT name FROM api.topics WHERE slug = $1 FOR UPDATE
+	`, slug).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", 0, fmt.Errorf("no topic with slug %q", slug)
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("find topic: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		DELETE FROM api.topic_verses
+		WHERE topic_id = (SELECT id FROM api.topics WHERE slug = $1)
+	`, slug)
+	if err != nil {
+		return "", 0, fmt.Errorf("delete topic verses: %w", err)
+	}
+	mappings, err := res.RowsAffected()
+	if err != nil {
+		return "", 0, fmt.Errorf("count deleted topic verses: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM api.topics WHERE slug = $1`, slug); err != nil {
+		return "", 0, fmt.Errorf("delete topic: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", 0, fmt.Errorf("commit: %w", err)
+	}
+	return name, mappings, nil
+}
@@ -15,6 +15,10 @@
 //
 // Usage:
 //   go run scripts/upsert_embeddings.go
+//
+// Embeddings are copied verbatim from api.verses; nothing is re-embedded here. They
+// must have been built with the same EMBEDDING_TEXT_TEMPLATE as enrichment/apply,
+// otherwise the index mixes incompatible vectors (see services.FormatEmbeddingText).
 
 package main
 
@@ -23,13 +27,13 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strings"
 
 	aiplatform "cloud.google.com/go/aiplatform/apiv1"
 	aiplatformpb "cloud.google.com/go/aiplatform/apiv1/aiplatformpb"
 	"github.com/joho/godotenv"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/sola-scriptura-search-api/pkg/schema/vertex"
 	"google.golang.org/api/option"
 )
 
@@ -87,12 +91,15 @@ func main() {
 	// Query all verses with embeddings
 	rows, err := db.QueryxContext(ctx, `
 		SELECT
-			verse_id,
-			book,
-			embedding::text as embedding_text
-		FROM api_views.mv_verses_search
-		WHERE embedding IS NOT NULL
-		ORDER BY book_order, chapter, verse
+			mv.verse_id,
+			mv.book,
+			b.testament,
+			b.book_order,
+			mv.embedding::text as embedding_text
+		FROM api_views.mv_verses_search mv
+		JOIN api.books b ON b.osis_id = mv.book
+		WHERE mv.embedding IS NOT NULL
+		ORDER BY mv.book_order, mv.chapter, mv.verse
 	`)
 	if err != nil {
 		log.Fatalf("Failed to query verses: %v", err)
@@ -104,29 +111,20 @@ func main() {
 	batchCount := 0
 
 	for rows.Next() {
-		var verseID, book, embeddingText string
-		if err := rows.Scan(&verseID, &book, &embeddingText); err != nil {
+		var verseID, book, testament, embeddingText string
+		var bookOrder int
+		if err := rows.Scan(&verseID, &book, &testament, &bookOrder, &embeddingText); err != nil {
 			log.Fatalf("Failed to scan row: %v", err)
 		}
 
 		// Parse embedding
-		embedding, err := parseEmbedding(embeddingText)
+		embedding, err := vertex.ParseEmbedding(embeddingText)
 		if err != nil {
 			log.Printf("Warning: failed to parse embedding for %s: %v", verseID, err)
 			continue
 		}
 
-		// Create datapoint with book as a restricts filter
-		dp := &aiplatformpb.IndexDatapoint{
-			DatapointId:   verseID,
-			FeatureVector: embedding,
-			Restricts: []*aiplatformpb.IndexDatapoint_Restriction{
-				{
-					Namespace:  "book",
-					AllowList:  []string{book},
-				},
-			},
-		}
+		dp := vertex.VerseDatapoint(verseID, book, testament, bookOrder, embedding)
 
 		batch = append(batch, dp)
 		totalCount++
@@ -167,27 +165,3 @@ func upsertBatch(ctx context.Context, client *aiplatform.IndexClient, indexName
 	_, err := client.UpsertDatapoints(ctx, req)
 	return err
 }
-
-// parseEmbedding parses a pgvector text representation like "[0.1,0.2,0.3]"
-func parseEmbedding(text string) ([]float32, error) {
-	text = strings.TrimPrefix(text, "[")
-	text = strings.TrimSuffix(text, "]")
-
-	if text == "" {
-		return nil, fmt.Errorf("empty embedding")
-	}
-
-	parts := strings.Split(text, ",")
-	result := make([]float32, len(parts))
-
-	for i, p := range parts {
-		var val float32
-		_, err := fmt.Sscanf(strings.TrimSpace(p), "%f", &val)
-		if err != nil {
-			return nil, fmt.Errorf("parse float at position %d: %w", i, err)
-		}
-		result[i] = val
-	}
-
-	return result, nil
-}